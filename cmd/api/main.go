@@ -1,14 +1,26 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/config"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/server"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache/redis"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/postgres"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/usecase"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/worker"
 	"github.com/Dang-Hai-Tran/newfeed-go/pkg/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/contentfilter"
 	"github.com/Dang-Hai-Tran/newfeed-go/pkg/database"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/mailer"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/oidc"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/token"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/webhook"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,26 +29,40 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	configPath := flag.String("config", "config/config.yaml", "path to the configuration file")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Initialize database
-	db, err := database.NewPostgresDB(cfg.Database)
+	db, err := database.NewPostgresDB(&cfg.DB)
 	if err != nil {
 		logger.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatalf("Failed to get database instance: %v", err)
+	}
+	defer sqlDB.Close()
 
 	// Initialize Redis cache
-	redisClient, err := cache.NewRedisClient(cfg.Redis)
+	redisClient, err := cache.NewRedisClient(&cfg.Redis)
 	if err != nil {
 		logger.Fatalf("Failed to connect to Redis: %v", err)
 	}
 	defer redisClient.Close()
 
+	// Initialize media storage
+	mediaStore := storage.NewLocalStore(cfg.Storage.Dir, cfg.Storage.BaseURL)
+
+	// Data exports are kept apart from media and only served through signed links
+	exportStore := storage.NewLocalStore(cfg.Export.Dir, "/exports")
+	importStore := storage.NewLocalStore(cfg.Import.Dir, "/imports")
+
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
 	userCache := redis.NewUserCache(redisClient)
@@ -46,24 +72,217 @@ func main() {
 	commentCache := redis.NewCommentCache(redisClient)
 	likeRepo := postgres.NewLikeRepository(db)
 	likeCache := redis.NewLikeCache(redisClient)
+	bookmarkCache := redis.NewBookmarkCache(redisClient)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	verificationTokenRepo := postgres.NewVerificationTokenRepository(db)
+	loginLockoutRepo := postgres.NewLoginLockoutRepository(db)
+	loginAttemptCache := redis.NewLoginAttemptCache(redisClient)
+	twoFactorRepo := postgres.NewTwoFactorRepository(db)
+	identityRepo := postgres.NewIdentityRepository(db)
+	oauthStateCache := redis.NewOAuthStateCache(redisClient)
+	reportRepo := postgres.NewReportRepository(db)
+	filterDecisionRepo := postgres.NewFilterDecisionRepository(db)
+	blockRepo := postgres.NewBlockRepository(db)
+	followRequestRepo := postgres.NewFollowRequestRepository(db)
+	closeFriendRepo := postgres.NewCloseFriendRepository(db)
+	bookmarkRepo := postgres.NewBookmarkRepository(db)
+	accountDeletionRepo := postgres.NewAccountDeletionRepository(db)
+	dataExportRepo := postgres.NewDataExportRepository(db)
+	importRepo := postgres.NewImportRepository(db)
+	suggestionRepo := postgres.NewSuggestionRepository(db)
+	suggestionCache := redis.NewSuggestionCache(redisClient)
+	graphRepo := postgres.NewGraphRepository(db)
+	privacyRepo := postgres.NewPrivacyRepository(db)
+
+	// Initialize token manager
+	tokenManager := token.NewManager(cfg.JWT.Secret, time.Duration(cfg.JWT.ExpirationHours)*time.Hour, cfg.TwoFactor.ChallengeExpiry)
+
+	// Initialize mailer
+	mail, err := mailer.NewMailer(&cfg.Mailer, logger)
+	if err != nil {
+		logger.Fatalf("Failed to initialize mailer: %v", err)
+	}
+
+	// Initialize social login providers
+	oauthProviders := make(map[string]*oidc.Provider, len(cfg.OAuth.Providers))
+	for name, providerConfig := range cfg.OAuth.Providers {
+		providerConfig := providerConfig
+		oauthProviders[name] = oidc.NewProvider(name, &providerConfig)
+	}
+
+	// Initialize content filters, cheap local checks run before the classifier
+	contentFilters := []contentfilter.Filter{
+		contentfilter.NewBannedWordFilter(cfg.ContentFilter.BannedWords, contentfilter.ParseDecision(cfg.ContentFilter.BannedWordsAction)),
+		contentfilter.NewLinkDomainFilter(cfg.ContentFilter.BlockedDomains, contentfilter.Reject),
+		contentfilter.NewLinkDensityFilter(cfg.ContentFilter.MaxLinks, cfg.ContentFilter.MaxLinkRatio),
+		contentfilter.NewDuplicateFilter(
+			usecase.NewDuplicateCounter(postRepo, commentRepo),
+			cfg.ContentFilter.DuplicateWindow,
+			cfg.ContentFilter.MaxDuplicates,
+		),
+	}
+	if cfg.ContentFilter.ClassifierURL != "" {
+		contentFilters = append(contentFilters, contentfilter.NewClassifierFilter(cfg.ContentFilter.ClassifierURL, cfg.ContentFilter.ClassifierTimeout))
+	}
+
+	// Initialize webhook sender
+	webhookSender := webhook.NewSender(&webhook.Config{
+		Timeout:             cfg.Webhook.Timeout,
+		MaxAttempts:         cfg.Webhook.MaxAttempts,
+		InitialBackoff:      cfg.Webhook.InitialBackoff,
+		MaxBackoff:          cfg.Webhook.MaxBackoff,
+		AllowPrivateTargets: cfg.Webhook.AllowPrivateTargets,
+	})
 
 	// Initialize usecases
-	userUsecase := usecase.NewUserUsecase(userRepo, userCache, cfg.Auth.TokenExpiry)
-	postUsecase := usecase.NewPostUsecase(postRepo, postCache, userRepo, cfg.Context.Timeout)
-	commentUsecase := usecase.NewCommentUsecase(commentRepo, commentCache, postRepo, userRepo, cfg.Context.Timeout)
-	likeUsecase := usecase.NewLikeUsecase(likeRepo, likeCache, postRepo, userRepo, cfg.Context.Timeout)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo, webhookSender, cfg.Webhook.MaxFailures, cfg.Context.Timeout)
+	twoFactorUsecase := usecase.NewTwoFactorUsecase(twoFactorRepo, userRepo, userCache, cfg.TwoFactor.Issuer, cfg.Context.Timeout)
+	verificationUsecase := usecase.NewVerificationUsecase(
+		verificationTokenRepo,
+		userRepo,
+		userCache,
+		twoFactorUsecase,
+		mail,
+		cfg.Verification.EmailTokenTTL,
+		cfg.Verification.ResetTokenTTL,
+		cfg.Verification.LinkBaseURL,
+		cfg.Context.Timeout,
+	)
+	loginGuard := usecase.NewLoginGuard(loginAttemptCache, loginLockoutRepo, usecase.LockoutPolicy{
+		MaxAccountFailures: cfg.Lockout.MaxAccountFailures,
+		MaxIPFailures:      cfg.Lockout.MaxIPFailures,
+		FailureWindow:      cfg.Lockout.FailureWindow,
+		LockoutDuration:    cfg.Lockout.LockoutDuration,
+		BaseDelay:          cfg.Lockout.BaseDelay,
+		MaxDelay:           cfg.Lockout.MaxDelay,
+	})
+	userUsecase := usecase.NewUserUsecase(
+		userRepo,
+		userCache,
+		webhookUsecase,
+		verificationUsecase,
+		loginGuard,
+		twoFactorUsecase,
+		tokenManager,
+		blockRepo,
+		followRequestRepo,
+		postRepo,
+		postCache,
+		mediaStore,
+		cfg.Storage.MaxImageSize,
+		cfg.Username.ChangeCooldown,
+		cfg.Username.ReservationPeriod,
+		cfg.Context.Timeout,
+	)
+	oauthUsecase := usecase.NewOAuthUsecase(
+		oauthProviders,
+		oauthStateCache,
+		identityRepo,
+		userRepo,
+		userCache,
+		verificationUsecase,
+		tokenManager,
+		cfg.OAuth.StateTTL,
+		cfg.Context.Timeout,
+	)
+	contentFilterUsecase := usecase.NewContentFilterUsecase(filterDecisionRepo, contentfilter.NewChain(contentFilters...), cfg.Context.Timeout)
+	audienceResolver := usecase.NewAudienceResolver(userRepo, closeFriendRepo, blockRepo)
+	postUsecase := usecase.NewPostUsecase(
+		postRepo,
+		postCache,
+		userRepo,
+		webhookUsecase,
+		contentFilterUsecase,
+		audienceResolver,
+		cfg.Retention.RestoreWindow,
+		cfg.Context.Timeout,
+	)
+	commentUsecase := usecase.NewCommentUsecase(commentRepo, commentCache, postRepo, userRepo, webhookUsecase, contentFilterUsecase, blockRepo, audienceResolver, cfg.Context.Timeout)
+	likeUsecase := usecase.NewLikeUsecase(likeRepo, likeCache, postRepo, userRepo, webhookUsecase, blockRepo, audienceResolver, cfg.Context.Timeout)
+	blockUsecase := usecase.NewBlockUsecase(blockRepo, userRepo, userCache, postCache, cfg.Context.Timeout)
+	followRequestUsecase := usecase.NewFollowRequestUsecase(followRequestRepo, userCache, webhookUsecase, cfg.Context.Timeout)
+	closeFriendUsecase := usecase.NewCloseFriendUsecase(closeFriendRepo, userRepo, blockRepo, postCache, cfg.Context.Timeout)
+	purgeUsecase := usecase.NewPurgeUsecase(userRepo, postRepo, commentRepo, mediaStore, cfg.Retention.RestoreWindow)
+	accountDeletionUsecase := usecase.NewAccountDeletionUsecase(
+		accountDeletionRepo,
+		userRepo,
+		postRepo,
+		commentRepo,
+		userCache,
+		postCache,
+		twoFactorUsecase,
+		mediaStore,
+		cfg.AccountDeletion.SigningKey,
+		cfg.AccountDeletion.LinkBaseURL,
+		cfg.AccountDeletion.StatusLinkTTL,
+		cfg.AccountDeletion.LeaseTimeout,
+		cfg.Context.Timeout,
+	)
+	dataExportUsecase := usecase.NewDataExportUsecase(
+		dataExportRepo,
+		userRepo,
+		mediaStore,
+		exportStore,
+		mail,
+		cfg.Export.SigningKey,
+		cfg.Export.LinkBaseURL,
+		cfg.Export.LinkTTL,
+		cfg.Export.LeaseTimeout,
+	)
+	importUsecase := usecase.NewImportUsecase(importRepo, importStore, cfg.Import.BatchSize, cfg.Import.MaxErrors, cfg.Import.LeaseTimeout)
+	suggestionUsecase := usecase.NewSuggestionUsecase(
+		suggestionRepo,
+		suggestionCache,
+		cfg.Suggestions.Size,
+		cfg.Suggestions.PreviewSize,
+		cfg.Suggestions.TTL,
+		cfg.Context.Timeout,
+	)
+	graphUsecase := usecase.NewGraphUsecase(graphRepo, userRepo, blockRepo)
+	privacyUsecase := usecase.NewPrivacyUsecase(privacyRepo, userRepo, blockRepo)
+	bookmarkUsecase := usecase.NewBookmarkUsecase(bookmarkRepo, bookmarkCache, postRepo, audienceResolver, cfg.Context.Timeout)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, userCache, postUsecase, commentUsecase, cfg.Context.Timeout)
+	moderationUsecase := usecase.NewModerationUsecase(
+		reportRepo,
+		postRepo,
+		commentRepo,
+		blockRepo,
+		audienceResolver,
+		postUsecase,
+		commentUsecase,
+		adminUsecase,
+		cfg.Moderation.AutoHideThreshold,
+		cfg.Context.Timeout,
+	)
 
 	// Setup router
 	routerConfig := &http.RouterConfig{
-		UserUsecase:    userUsecase,
-		PostUsecase:    postUsecase,
-		CommentUsecase: commentUsecase,
-		LikeUsecase:    likeUsecase,
-		Logger:         logger,
-		JWTSecret:      cfg.Auth.JWTSecret,
-		AllowOrigins:   cfg.CORS.AllowOrigins,
-		RateLimit:      cfg.RateLimit.Rate,
-		RateBurst:      cfg.RateLimit.Burst,
+		UserUsecase:            userUsecase,
+		PostUsecase:            postUsecase,
+		CommentUsecase:         commentUsecase,
+		LikeUsecase:            likeUsecase,
+		WebhookUsecase:         webhookUsecase,
+		VerificationUsecase:    verificationUsecase,
+		TwoFactorUsecase:       twoFactorUsecase,
+		OAuthUsecase:           oauthUsecase,
+		AdminUsecase:           adminUsecase,
+		ModerationUsecase:      moderationUsecase,
+		ContentFilterUsecase:   contentFilterUsecase,
+		BlockUsecase:           blockUsecase,
+		FollowRequestUsecase:   followRequestUsecase,
+		CloseFriendUsecase:     closeFriendUsecase,
+		BookmarkUsecase:        bookmarkUsecase,
+		AccountDeletionUsecase: accountDeletionUsecase,
+		DataExportUsecase:      dataExportUsecase,
+		ImportUsecase:          importUsecase,
+		SuggestionUsecase:      suggestionUsecase,
+		GraphUsecase:           graphUsecase,
+		PrivacyUsecase:         privacyUsecase,
+		Logger:                 logger,
+		JWTSecret:              cfg.JWT.Secret,
+		AllowOrigins:           cfg.CORS.AllowOrigins,
+		RateLimit:              cfg.RateLimit.Rate,
+		RateBurst:              cfg.RateLimit.Burst,
 	}
 	router := http.SetupRouter(routerConfig)
 
@@ -74,10 +293,38 @@ func main() {
 	serverConfig := &server.Config{
 		Host:            cfg.Server.Host,
 		Port:            cfg.Server.Port,
-		ReadTimeout:     time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout:    time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		ReadTimeout:     cfg.Server.ReadTimeout,
+		WriteTimeout:    cfg.Server.WriteTimeout,
 		MaxHeaderBytes:  cfg.Server.MaxHeaderBytes,
-		GracefulTimeout: time.Duration(cfg.Server.GracefulTimeout) * time.Second,
+		GracefulTimeout: cfg.Server.GracefulTimeout,
+	}
+
+	// Start the background workers
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		scheduler := worker.NewPostScheduler(postUsecase, logger, cfg.Scheduler.Interval, cfg.Scheduler.BatchSize)
+		go scheduler.Run(schedulerCtx)
+	}
+	if cfg.Retention.PurgeEnabled {
+		purger := worker.NewPurger(purgeUsecase, logger, cfg.Retention.PurgeInterval, cfg.Retention.PurgeBatchSize)
+		go purger.Run(schedulerCtx)
+	}
+	if cfg.AccountDeletion.Enabled {
+		accountDeleter := worker.NewAccountDeleter(accountDeletionUsecase, logger, cfg.AccountDeletion.Interval, cfg.AccountDeletion.BatchSize)
+		go accountDeleter.Run(schedulerCtx)
+	}
+	if cfg.Export.Enabled {
+		dataExporter := worker.NewDataExporter(dataExportUsecase, logger, cfg.Export.Interval, cfg.Export.BatchSize)
+		go dataExporter.Run(schedulerCtx)
+	}
+	if cfg.Import.Enabled {
+		importer := worker.NewImporter(importUsecase, logger, cfg.Import.Interval)
+		go importer.Run(schedulerCtx)
+	}
+	if cfg.Suggestions.Enabled {
+		suggestionRefresher := worker.NewSuggestionRefresher(suggestionUsecase, logger, cfg.Suggestions.Interval, cfg.Suggestions.BatchSize)
+		go suggestionRefresher.Run(schedulerCtx)
 	}
 
 	srv := server.NewServer(router, logger, serverConfig)
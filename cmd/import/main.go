@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/postgres"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/usecase"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/database"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+)
+
+// import loads an NDJSON file of users, posts, comments, likes and follows
+// exported from another community. Run it with -dry-run first to list the
+// records that would be rejected.
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "path to the configuration file")
+	file := flag.String("file", "", "NDJSON file to import")
+	source := flag.String("source", "", "name of the imported community, record IDs are unique within it")
+	dryRun := flag.Bool("dry-run", false, "validate the file without importing anything")
+	resume := flag.Uint64("resume", 0, "ID of a failed or interrupted import to resume")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.NewPostgresDB(&cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Import files are kept next to the ones uploaded through the API
+	importStore := storage.NewLocalStore(cfg.Import.Dir, "/imports")
+	importUsecase := usecase.NewImportUsecase(
+		postgres.NewImportRepository(db),
+		importStore,
+		cfg.Import.BatchSize,
+		cfg.Import.MaxErrors,
+		cfg.Import.LeaseTimeout,
+	)
+
+	importID := *resume
+	if importID == 0 {
+		if *file == "" || *source == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Failed to open import file: %v", err)
+		}
+		job, err := importUsecase.CreateImport(*source, *dryRun, f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Failed to create import: %v", err)
+		}
+		importID = job.ID
+		log.Printf("Created import %d, run again with -resume %d if it is interrupted", importID, importID)
+	}
+
+	job, err := importUsecase.RunImport(importID)
+	if job != nil {
+		printSummary(job)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
+
+// printSummary prints the counters and kept validation errors of an import
+func printSummary(job *domain.ImportJob) {
+	mode := "Imported"
+	if job.DryRun {
+		mode = "Validated"
+	}
+	fmt.Printf("%s %d users, %d posts, %d comments, %d likes and %d follows from %s\n",
+		mode, job.UsersImported, job.PostsImported, job.CommentsImported, job.LikesImported, job.FollowsImported, job.Source)
+	fmt.Printf("Skipped %d records imported before\n", job.Skipped)
+
+	if job.ErrorCount > 0 {
+		fmt.Printf("%d records were rejected:\n", job.ErrorCount)
+		for _, message := range job.ErrorList() {
+			fmt.Println("  " + message)
+		}
+		if kept := int64(len(job.ErrorList())); job.ErrorCount > kept {
+			fmt.Printf("  ... and %d more\n", job.ErrorCount-kept)
+		}
+	}
+}
@@ -7,16 +7,54 @@ import (
 
 type Config struct {
 	Server   ServerConfig
+	Context  ContextConfig
+	CORS     CORSConfig
+	RateLimit RateLimitConfig
 	DB       DBConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
-	LogLevel string
+	Webhook      WebhookConfig
+	Mailer       MailerConfig
+	Verification VerificationConfig
+	Lockout      LockoutConfig
+	TwoFactor    TwoFactorConfig
+	OAuth        OAuthConfig
+	Moderation   ModerationConfig
+	ContentFilter ContentFilterConfig
+	Scheduler    SchedulerConfig
+	Retention    RetentionConfig
+	Storage      StorageConfig
+	AccountDeletion AccountDeletionConfig
+	Export       ExportConfig
+	Import       ImportConfig
+	Suggestions  SuggestionsConfig
+	Username     UsernameConfig
+	LogLevel     string
 }
 
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	MaxHeaderBytes  int
+	GracefulTimeout time.Duration
+}
+
+// ContextConfig sets how long a request may spend in the usecases
+type ContextConfig struct {
+	Timeout time.Duration
+}
+
+type CORSConfig struct {
+	AllowOrigins []string
+}
+
+// RateLimitConfig allows Rate requests per second to each client with bursts
+// of up to Burst requests
+type RateLimitConfig struct {
+	Rate  float64
+	Burst int
 }
 
 type DBConfig struct {
@@ -42,6 +80,163 @@ type JWTConfig struct {
 	RefreshDuration  time.Duration
 }
 
+// WebhookConfig controls webhook deliveries. AllowPrivateTargets accepts http
+// receivers on private addresses and is only meant for local development.
+type WebhookConfig struct {
+	Timeout             time.Duration
+	MaxAttempts         int
+	InitialBackoff      time.Duration
+	MaxBackoff          time.Duration
+	MaxFailures         int
+	AllowPrivateTargets bool
+}
+
+type MailerConfig struct {
+	Driver   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	FilePath string
+}
+
+type VerificationConfig struct {
+	EmailTokenTTL time.Duration
+	ResetTokenTTL time.Duration
+	LinkBaseURL   string
+}
+
+type LockoutConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+}
+
+type TwoFactorConfig struct {
+	Issuer          string
+	ChallengeExpiry time.Duration
+}
+
+type OAuthConfig struct {
+	StateTTL  time.Duration
+	Providers map[string]OAuthProviderConfig
+}
+
+// OAuthProviderConfig configures a social login provider. Endpoints are
+// discovered from the issuer unless they are set explicitly.
+type OAuthProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AuthURL      string
+	TokenURL     string
+	JWKSURL      string
+	UserInfoURL  string
+}
+
+type ModerationConfig struct {
+	AutoHideThreshold int
+}
+
+type ContentFilterConfig struct {
+	BannedWords       []string
+	BannedWordsAction string
+	BlockedDomains    []string
+	MaxLinks          int
+	MaxLinkRatio      float64
+	DuplicateWindow   time.Duration
+	MaxDuplicates     int
+	ClassifierURL     string
+	ClassifierTimeout time.Duration
+}
+
+type SchedulerConfig struct {
+	Enabled   bool
+	Interval  time.Duration
+	BatchSize int
+}
+
+// RetentionConfig controls how long deleted rows can be restored before the
+// purger removes them
+type RetentionConfig struct {
+	RestoreWindow  time.Duration
+	PurgeEnabled   bool
+	PurgeInterval  time.Duration
+	PurgeBatchSize int
+}
+
+// AccountDeletionConfig controls the worker removing the content of deleted
+// accounts. A deletion not updated for LeaseTimeout is resumed by another worker.
+// The deleted user follows the progress through a status link signed with
+// SigningKey that expires after StatusLinkTTL.
+type AccountDeletionConfig struct {
+	Enabled       bool
+	Interval      time.Duration
+	BatchSize     int
+	LeaseTimeout  time.Duration
+	LinkBaseURL   string
+	StatusLinkTTL time.Duration
+	SigningKey    string
+}
+
+// ExportConfig controls data exports. Download links are signed with
+// SigningKey and expire after LinkTTL, together with the archive.
+type ExportConfig struct {
+	Enabled      bool
+	Dir          string
+	LinkBaseURL  string
+	LinkTTL      time.Duration
+	SigningKey   string
+	Interval     time.Duration
+	BatchSize    int
+	LeaseTimeout time.Duration
+}
+
+// ImportConfig controls imports of other communities. Imports commit
+// BatchSize records at a time and keep the first MaxErrors validation errors.
+type ImportConfig struct {
+	Enabled      bool
+	Dir          string
+	Interval     time.Duration
+	BatchSize    int
+	MaxErrors    int
+	LeaseTimeout time.Duration
+}
+
+// SuggestionsConfig controls follow suggestions. Up to Size suggestions are
+// precomputed per user every Interval and kept in Redis for TTL, each with
+// up to PreviewSize mutual connections.
+type SuggestionsConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	BatchSize   int
+	Size        int
+	PreviewSize int
+	TTL         time.Duration
+}
+
+// UsernameConfig controls username changes. A user can change their username
+// once per ChangeCooldown, and the former username stays reserved for them
+// for ReservationPeriod.
+type UsernameConfig struct {
+	ChangeCooldown    time.Duration
+	ReservationPeriod time.Duration
+}
+
+// StorageConfig controls where media files are kept. Uploaded profile images
+// are limited to MaxImageSize bytes.
+type StorageConfig struct {
+	Dir          string
+	BaseURL      string
+	MaxImageSize int64
+}
+
 func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigFile(path)
 	viper.AutomaticEnv()
@@ -12,25 +12,153 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
+	Username       string `json:"username" binding:"required_without=ChallengeToken"`
+	Password       string `json:"password" binding:"required_without=ChallengeToken"`
+	Code           string `json:"code" binding:"required_with=ChallengeToken"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// UpdateProfileRequest changes the fields it contains, omitted fields are kept
 type UpdateProfileRequest struct {
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Birthday  time.Time `json:"birthday"`
-	Password  string    `json:"password"`
+	FirstName *string    `json:"first_name" binding:"omitempty,max=50"`
+	LastName  *string    `json:"last_name" binding:"omitempty,max=50"`
+	Birthday  *time.Time `json:"birthday"`
+	Bio       *string    `json:"bio" binding:"omitempty,max=160"`
+	Website   *string    `json:"website" binding:"omitempty,max=200"`
+	Location  *string    `json:"location" binding:"omitempty,max=100"`
+	Pronouns  *string    `json:"pronouns" binding:"omitempty,max=30"`
+}
+
+// ChangePasswordRequest sets a new password. Code is a TOTP or recovery code,
+// required when 2FA is enabled.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+	Code            string `json:"code"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+}
+
+// ChangeEmailRequest asks to move the account to a new address. Code is a
+// TOTP or recovery code, required when 2FA is enabled.
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UpdatePrivacyRequest changes the audiences it contains, omitted fields are kept
+type UpdatePrivacyRequest struct {
+	Email    *string `json:"email" binding:"omitempty,oneof=public followers only_me"`
+	Birthday *string `json:"birthday" binding:"omitempty,oneof=public followers only_me"`
+	Location *string `json:"location" binding:"omitempty,oneof=public followers only_me"`
+	Website  *string `json:"website" binding:"omitempty,oneof=public followers only_me"`
+	Pronouns *string `json:"pronouns" binding:"omitempty,oneof=public followers only_me"`
+}
+
+type SetPrivateRequest struct {
+	IsPrivate *bool `json:"is_private" binding:"required"`
 }
 
 type CreatePostRequest struct {
-	Content  string `json:"content" binding:"required"`
-	ImageURL string `json:"image_url"`
+	Content    string `json:"content" binding:"required"`
+	ImageURL   string `json:"image_url"`
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public followers close_friends only_me"`
+}
+
+// DraftRequest creates or replaces a draft. Setting publish_at schedules it.
+type DraftRequest struct {
+	Content    string     `json:"content" binding:"required"`
+	ImageURL   string     `json:"image_url"`
+	Visibility string     `json:"visibility" binding:"omitempty,oneof=public followers close_friends only_me"`
+	PublishAt  *time.Time `json:"publish_at"`
+}
+
+// SharePostRequest reposts a post, or quotes it when content is set
+type SharePostRequest struct {
+	Content    string `json:"content"`
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public followers close_friends only_me"`
+}
+
+// BookmarkRequest files a bookmark in a collection, or leaves it uncollected
+type BookmarkRequest struct {
+	CollectionID *uint64 `json:"collection_id"`
+}
+
+// DeleteAccountRequest confirms an account deletion. Code is a TOTP or
+// recovery code, required when 2FA is enabled.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"`
+}
+
+// DeletionStatusQuery carries the expiry and signature of a signed account
+// deletion status link
+type DeletionStatusQuery struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// DataExportRequest asks for a data export, optionally with a page readable in a browser
+type DataExportRequest struct {
+	IncludeHTML bool `json:"include_html"`
+}
+
+// DownloadQuery carries the expiry and signature of a signed download link
+type DownloadQuery struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// ImportQuery names the source of an uploaded import file. Records keep their
+// source IDs, so importing a source again skips what was already imported.
+type ImportQuery struct {
+	Source string `form:"source" binding:"required,max=100"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type CreateBookmarkCollectionRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// BookmarkQuery pages through bookmarks with the next_cursor of the previous page
+type BookmarkQuery struct {
+	CollectionID *uint64 `form:"collection_id"`
+	Cursor       uint64  `form:"cursor"`
+	Limit        int     `form:"limit,default=10" binding:"min=1,max=100"`
 }
 
 type UpdatePostRequest struct {
-	Content  string `json:"content"`
-	ImageURL string `json:"image_url"`
+	Content    string `json:"content"`
+	ImageURL   string `json:"image_url"`
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public followers close_friends only_me"`
 }
 
 type CreateCommentRequest struct {
@@ -41,7 +169,53 @@ type UpdateCommentRequest struct {
 	Content string `json:"content" binding:"required"`
 }
 
+type OAuthCallbackQuery struct {
+	State string `form:"state" binding:"required"`
+	Code  string `form:"code" binding:"required"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+type CreateReportRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam harassment hate_speech violence nudity misinformation other"`
+	Details string `json:"details" binding:"max=1000"`
+}
+
+type ModerationActionRequest struct {
+	Action string `json:"action" binding:"required,oneof=hide restore remove warn suspend dismiss"`
+	Note   string `json:"note" binding:"max=1000"`
+}
+
+type FilterDecisionQuery struct {
+	Decision string `form:"decision" binding:"omitempty,oneof=flag reject"`
+	PaginationQuery
+}
+
+// SuggestionQuery limits how many follow suggestions are returned
+type SuggestionQuery struct {
+	Limit int `form:"limit,default=10" binding:"min=1,max=50"`
+}
+
 type PaginationQuery struct {
 	Page  int `form:"page,default=1" binding:"min=1"`
 	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
 }
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Secret string   `json:"secret" binding:"required,min=16"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    string   `json:"url" binding:"omitempty,url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret" binding:"omitempty,min=16"`
+	Active *bool    `json:"active"`
+}
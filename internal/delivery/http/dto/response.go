@@ -12,14 +12,102 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// UserResponse is a user as seen by a viewer. The email, birthday, website,
+// location and pronouns are left out when the user hides them from the viewer.
 type UserResponse struct {
-	ID        uint64    `json:"id"`
-	Username  string    `json:"username"`
+	ID            uint64     `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	Role          string     `json:"role"`
+	IsPrivate     bool       `json:"is_private"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	Birthday      *time.Time `json:"birthday,omitempty"`
+	AvatarURL     string     `json:"avatar_url,omitempty"`
+	CoverURL      string     `json:"cover_url,omitempty"`
+	Bio           string     `json:"bio"`
+	Website       string     `json:"website,omitempty"`
+	Location      string     `json:"location,omitempty"`
+	Pronouns      string     `json:"pronouns,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Stats are only included on profile pages
+	Stats *ProfileStatsResponse `json:"stats,omitempty"`
+}
+
+type PrivacySettingsResponse struct {
 	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Birthday  time.Time `json:"birthday"`
-	CreatedAt time.Time `json:"created_at"`
+	Birthday  string    `json:"birthday"`
+	Location  string    `json:"location"`
+	Website   string    `json:"website"`
+	Pronouns  string    `json:"pronouns"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ProfileStatsResponse struct {
+	PostCount      int64 `json:"post_count"`
+	FollowersCount int64 `json:"followers_count"`
+	FollowingCount int64 `json:"following_count"`
+}
+
+// FollowUserResponse is a row of a followers or following list
+type FollowUserResponse struct {
+	ID           uint64    `json:"id"`
+	Username     string    `json:"username"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	AvatarURL    string    `json:"avatar_url,omitempty"`
+	IsPrivate    bool      `json:"is_private"`
+	FollowedAt   time.Time `json:"followed_at"`
+	FollowedByMe bool      `json:"followed_by_me"`
+	FollowsMe    bool      `json:"follows_me"`
+}
+
+// UserSummaryResponse is a user shown inside another resource
+type UserSummaryResponse struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	IsPrivate bool   `json:"is_private"`
+}
+
+// SuggestionResponse is an account the user may know, with a few of the
+// accounts the user follows that follow it
+type SuggestionResponse struct {
+	User          *UserSummaryResponse   `json:"user"`
+	Reason        string                 `json:"reason"`
+	MutualCount   int64                  `json:"mutual_count"`
+	MutualPreview []*UserSummaryResponse `json:"mutual_preview"`
+}
+
+// RelationshipResponse is how a user relates to another user. Fields only
+// shown to the two users are left out for other viewers.
+type RelationshipResponse struct {
+	UserID             uint64                 `json:"user_id"`
+	OtherUserID        uint64                 `json:"other_user_id"`
+	Following          bool                   `json:"following"`
+	FollowedBy         bool                   `json:"followed_by"`
+	IsFriend           bool                   `json:"is_friend"`
+	MutualFriendsCount int64                  `json:"mutual_friends_count"`
+	MutualFriends      []*UserSummaryResponse `json:"mutual_friends"`
+	Degree             *int                   `json:"degree"`
+
+	FollowRequested       *bool `json:"follow_requested,omitempty"`
+	FollowRequestReceived *bool `json:"follow_request_received,omitempty"`
+	Blocking              *bool `json:"blocking,omitempty"`
+	BlockedBy             *bool `json:"blocked_by,omitempty"`
+	Muting                *bool `json:"muting,omitempty"`
+	CloseFriend           *bool `json:"close_friend,omitempty"`
+}
+
+// AdminUserResponse adds account state only shown to moderators and admins
+type AdminUserResponse struct {
+	*UserResponse
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
 }
 
 type PostResponse struct {
@@ -27,6 +115,15 @@ type PostResponse struct {
 	UserID    uint64          `json:"user_id"`
 	Content   string          `json:"content"`
 	ImageURL  string          `json:"image_url,omitempty"`
+	Visibility string         `json:"visibility"`
+	Status    string          `json:"status"`
+	PublishAt *time.Time      `json:"publish_at,omitempty"`
+	OriginalPostID *uint64    `json:"original_post_id,omitempty"`
+	OriginalPost *PostResponse `json:"original_post,omitempty"`
+	ShareCount int64          `json:"share_count"`
+	Edited    bool            `json:"edited"`
+	EditCount int64           `json:"edit_count"`
+	BookmarkedByMe bool       `json:"bookmarked_by_me"`
 	Likes     []LikeResponse  `json:"likes,omitempty"`
 	Comments  []CommentResponse `json:"comments,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
@@ -38,6 +135,8 @@ type CommentResponse struct {
 	PostID    uint64    `json:"post_id"`
 	UserID    uint64    `json:"user_id"`
 	Content   string    `json:"content"`
+	Edited    bool      `json:"edited"`
+	EditCount int64     `json:"edit_count"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -50,19 +149,304 @@ type LikeResponse struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token             string `json:"token,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+}
+
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type IdentityResponse struct {
+	ID        uint64    `json:"id"`
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FollowResponse struct {
+	Status string `json:"status"`
+}
+
+type FollowRequestResponse struct {
+	ID        uint64               `json:"id"`
+	Requester *UserSummaryResponse `json:"requester"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type PostRevisionResponse struct {
+	ID       uint64    `json:"id"`
+	Content  string    `json:"content"`
+	ImageURL string    `json:"image_url,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type CommentRevisionResponse struct {
+	ID       uint64    `json:"id"`
+	Content  string    `json:"content"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type BookmarkResponse struct {
+	ID           uint64        `json:"id"`
+	CollectionID *uint64       `json:"collection_id,omitempty"`
+	Post         *PostResponse `json:"post"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type BookmarkPageResponse struct {
+	Bookmarks  []*BookmarkResponse `json:"bookmarks"`
+	NextCursor uint64              `json:"next_cursor,omitempty"`
+}
+
+type BookmarkCollectionResponse struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReportResponse struct {
+	ID         uint64     `json:"id"`
+	ReporterID uint64     `json:"reporter_id"`
+	TargetType string     `json:"target_type"`
+	TargetID   uint64     `json:"target_id"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details,omitempty"`
+	Status     string     `json:"status"`
+	ResolvedBy *uint64    `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ModerationActionResponse struct {
+	ID           uint64    `json:"id"`
+	ModeratorID  *uint64   `json:"moderator_id,omitempty"`
+	TargetType   string    `json:"target_type"`
+	TargetID     uint64    `json:"target_id"`
+	TargetUserID uint64    `json:"target_user_id"`
+	Action       string    `json:"action"`
+	Note         string    `json:"note,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// WarningResponse is a warning as shown to its recipient, without the moderator
+type WarningResponse struct {
+	ID         uint64    `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   uint64    `json:"target_id"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookResponse struct {
+	ID                  uint64     `json:"id"`
+	URL                 string     `json:"url"`
+	Events              []string   `json:"events"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID           uint64     `json:"id"`
+	WebhookID    uint64     `json:"webhook_id"`
+	Event        string     `json:"event"`
+	Payload      string     `json:"payload"`
+	Attempts     int        `json:"attempts"`
+	StatusCode   int        `json:"status_code"`
+	ResponseBody string     `json:"response_body,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	Success      bool       `json:"success"`
+	RedeliveryOf *uint64    `json:"redelivery_of,omitempty"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AccountDeletionResponse reports the progress of an account deletion
+type AccountDeletionResponse struct {
+	ID              uint64     `json:"id"`
+	UserID          uint64     `json:"user_id"`
+	Status          string     `json:"status"`
+	Step            string     `json:"step"`
+	Progress        int        `json:"progress"`
+	PostsDeleted    int64      `json:"posts_deleted"`
+	CommentsDeleted int64      `json:"comments_deleted"`
+	LikesDeleted    int64      `json:"likes_deleted"`
+	FollowsDeleted  int64      `json:"follows_deleted"`
+	RecordsDeleted  int64      `json:"records_deleted"`
+	Attempts        int        `json:"attempts"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	StatusURL       string     `json:"status_url,omitempty"`
+}
+
+type DataExportResponse struct {
+	ID          uint64     `json:"id"`
+	Status      string     `json:"status"`
+	IncludeHTML bool       `json:"include_html"`
+	Size        int64      `json:"size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type ImportJobResponse struct {
+	ID               uint64     `json:"id"`
+	Source           string     `json:"source"`
+	DryRun           bool       `json:"dry_run"`
+	Status           string     `json:"status"`
+	Stage            string     `json:"stage"`
+	Line             int64      `json:"line"`
+	UsersImported    int64      `json:"users_imported"`
+	PostsImported    int64      `json:"posts_imported"`
+	CommentsImported int64      `json:"comments_imported"`
+	LikesImported    int64      `json:"likes_imported"`
+	FollowsImported  int64      `json:"follows_imported"`
+	Skipped          int64      `json:"skipped"`
+	ErrorCount       int64      `json:"error_count"`
+	Errors           []string   `json:"errors,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
 }
 
 // Convert domain models to response DTOs
 func ToUserResponse(user *domain.User) *UserResponse {
 	return &UserResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		IsPrivate:     user.IsPrivate,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Birthday:      &user.Birthday,
+		AvatarURL:     user.AvatarURL,
+		CoverURL:      user.CoverURL,
+		Bio:           user.Bio,
+		Website:       user.Website,
+		Location:      user.Location,
+		Pronouns:      user.Pronouns,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+// ToProfileResponse builds the profile page of a user for a viewer with
+// access, leaving out the fields hidden from them, and adds the stats
+func ToProfileResponse(user *domain.User, stats *domain.ProfileStats, access *domain.ProfileAccess) *UserResponse {
+	response := ToUserResponse(user)
+	if !access.Email {
+		response.Email = ""
+		response.EmailVerified = false
+	}
+	if !access.Birthday {
+		response.Birthday = nil
+	}
+	if !access.Location {
+		response.Location = ""
+	}
+	if !access.Website {
+		response.Website = ""
+	}
+	if !access.Pronouns {
+		response.Pronouns = ""
+	}
+	response.Stats = &ProfileStatsResponse{
+		PostCount:      stats.Posts,
+		FollowersCount: stats.Followers,
+		FollowingCount: stats.Following,
+	}
+	return response
+}
+
+func ToPrivacySettingsResponse(settings *domain.PrivacySettings) *PrivacySettingsResponse {
+	return &PrivacySettingsResponse{
+		Email:     settings.Email,
+		Birthday:  settings.Birthday,
+		Location:  settings.Location,
+		Website:   settings.Website,
+		Pronouns:  settings.Pronouns,
+		UpdatedAt: settings.UpdatedAt,
+	}
+}
+
+func ToFollowUserResponse(entry *domain.FollowListEntry) *FollowUserResponse {
+	return &FollowUserResponse{
+		ID:           entry.ID,
+		Username:     entry.Username,
+		FirstName:    entry.FirstName,
+		LastName:     entry.LastName,
+		AvatarURL:    entry.AvatarURL,
+		IsPrivate:    entry.IsPrivate,
+		FollowedAt:   entry.FollowedAt,
+		FollowedByMe: entry.FollowedByMe,
+		FollowsMe:    entry.FollowsMe,
+	}
+}
+
+func ToUserSummaryResponse(user *domain.User) *UserSummaryResponse {
+	return &UserSummaryResponse{
 		ID:        user.ID,
 		Username:  user.Username,
-		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
-		Birthday:  user.Birthday,
-		CreatedAt: user.CreatedAt,
+		AvatarURL: user.AvatarURL,
+		IsPrivate: user.IsPrivate,
+	}
+}
+
+func ToSuggestionResponse(suggestion *domain.Suggestion) *SuggestionResponse {
+	mutualPreview := make([]*UserSummaryResponse, len(suggestion.MutualPreview))
+	for i, user := range suggestion.MutualPreview {
+		mutualPreview[i] = ToUserSummaryResponse(&user)
+	}
+
+	return &SuggestionResponse{
+		User:          ToUserSummaryResponse(&suggestion.User),
+		Reason:        suggestion.Reason,
+		MutualCount:   suggestion.MutualCount,
+		MutualPreview: mutualPreview,
+	}
+}
+
+func ToRelationshipResponse(relationship *domain.Relationship) *RelationshipResponse {
+	mutualFriends := make([]*UserSummaryResponse, len(relationship.MutualFriends))
+	for i, user := range relationship.MutualFriends {
+		mutualFriends[i] = ToUserSummaryResponse(&user)
+	}
+
+	return &RelationshipResponse{
+		UserID:                relationship.UserID,
+		OtherUserID:           relationship.OtherUserID,
+		Following:             relationship.Following,
+		FollowedBy:            relationship.FollowedBy,
+		IsFriend:              relationship.IsFriend,
+		MutualFriendsCount:    relationship.MutualFriendsCount,
+		MutualFriends:         mutualFriends,
+		Degree:                relationship.Degree,
+		FollowRequested:       relationship.FollowRequested,
+		FollowRequestReceived: relationship.FollowRequestReceived,
+		Blocking:              relationship.Blocking,
+		BlockedBy:             relationship.BlockedBy,
+		Muting:                relationship.Muting,
+		CloseFriend:           relationship.CloseFriend,
+	}
+}
+
+func ToAdminUserResponse(user *domain.User) *AdminUserResponse {
+	return &AdminUserResponse{
+		UserResponse:     ToUserResponse(user),
+		SuspendedAt:      user.SuspendedAt,
+		SuspensionReason: user.SuspensionReason,
 	}
 }
 
@@ -77,11 +461,24 @@ func ToPostResponse(post *domain.Post) *PostResponse {
 		comments[i] = *ToCommentResponse(&comment)
 	}
 
+	var original *PostResponse
+	if post.OriginalPost != nil {
+		original = ToPostResponse(post.OriginalPost)
+	}
+
 	return &PostResponse{
 		ID:        post.ID,
 		UserID:    post.UserID,
 		Content:   post.Content,
 		ImageURL:  post.ImageURL,
+		Visibility: post.Visibility,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
+		OriginalPostID: post.OriginalPostID,
+		OriginalPost: original,
+		ShareCount: post.ShareCount,
+		Edited:    post.EditCount > 0,
+		EditCount: post.EditCount,
 		Likes:     likes,
 		Comments:  comments,
 		CreatedAt: post.CreatedAt,
@@ -89,12 +486,22 @@ func ToPostResponse(post *domain.Post) *PostResponse {
 	}
 }
 
+// MarkBookmarked sets BookmarkedByMe on the post and the post it shares
+func (r *PostResponse) MarkBookmarked(bookmarked map[uint64]bool) {
+	r.BookmarkedByMe = bookmarked[r.ID]
+	if r.OriginalPost != nil {
+		r.OriginalPost.MarkBookmarked(bookmarked)
+	}
+}
+
 func ToCommentResponse(comment *domain.Comment) *CommentResponse {
 	return &CommentResponse{
 		ID:        comment.ID,
 		PostID:    comment.PostID,
 		UserID:    comment.UserID,
 		Content:   comment.Content,
+		Edited:    comment.EditCount > 0,
+		EditCount: comment.EditCount,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
 	}
@@ -108,3 +515,186 @@ func ToLikeResponse(like *domain.Like) *LikeResponse {
 		CreatedAt: like.CreatedAt,
 	}
 }
+
+func ToIdentityResponse(identity *domain.UserIdentity) *IdentityResponse {
+	return &IdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+func ToFollowRequestResponse(request *domain.FollowRequest) *FollowRequestResponse {
+	response := &FollowRequestResponse{
+		ID:        request.ID,
+		CreatedAt: request.CreatedAt,
+	}
+	if request.Requester != nil {
+		response.Requester = ToUserSummaryResponse(request.Requester)
+	}
+	return response
+}
+
+func ToPostRevisionResponse(revision *domain.PostRevision) *PostRevisionResponse {
+	return &PostRevisionResponse{
+		ID:       revision.ID,
+		Content:  revision.Content,
+		ImageURL: revision.ImageURL,
+		EditedAt: revision.EditedAt,
+	}
+}
+
+func ToCommentRevisionResponse(revision *domain.CommentRevision) *CommentRevisionResponse {
+	return &CommentRevisionResponse{
+		ID:       revision.ID,
+		Content:  revision.Content,
+		EditedAt: revision.EditedAt,
+	}
+}
+
+func ToBookmarkResponse(bookmark *domain.Bookmark) *BookmarkResponse {
+	response := &BookmarkResponse{
+		ID:           bookmark.ID,
+		CollectionID: bookmark.CollectionID,
+		CreatedAt:    bookmark.CreatedAt,
+	}
+	if bookmark.Post != nil {
+		response.Post = ToPostResponse(bookmark.Post)
+	}
+	return response
+}
+
+func ToBookmarkCollectionResponse(collection *domain.BookmarkCollection) *BookmarkCollectionResponse {
+	return &BookmarkCollectionResponse{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		CreatedAt: collection.CreatedAt,
+	}
+}
+
+func ToReportResponse(report *domain.Report) *ReportResponse {
+	return &ReportResponse{
+		ID:         report.ID,
+		ReporterID: report.ReporterID,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		ResolvedBy: report.ResolvedBy,
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
+
+func ToModerationActionResponse(action *domain.ModerationAction) *ModerationActionResponse {
+	return &ModerationActionResponse{
+		ID:           action.ID,
+		ModeratorID:  action.ModeratorID,
+		TargetType:   action.TargetType,
+		TargetID:     action.TargetID,
+		TargetUserID: action.TargetUserID,
+		Action:       action.Action,
+		Note:         action.Note,
+		CreatedAt:    action.CreatedAt,
+	}
+}
+
+func ToWarningResponse(action *domain.ModerationAction) *WarningResponse {
+	return &WarningResponse{
+		ID:         action.ID,
+		TargetType: action.TargetType,
+		TargetID:   action.TargetID,
+		Note:       action.Note,
+		CreatedAt:  action.CreatedAt,
+	}
+}
+
+func ToWebhookResponse(webhook *domain.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:                  webhook.ID,
+		URL:                 webhook.URL,
+		Events:              webhook.EventList(),
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
+
+func ToWebhookDeliveryResponse(delivery *domain.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:           delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		Event:        delivery.Event,
+		Payload:      delivery.Payload,
+		Attempts:     delivery.Attempts,
+		StatusCode:   delivery.StatusCode,
+		ResponseBody: delivery.ResponseBody,
+		Error:        delivery.Error,
+		Success:      delivery.Success,
+		RedeliveryOf: delivery.RedeliveryOf,
+		DeliveredAt:  delivery.DeliveredAt,
+		CreatedAt:    delivery.CreatedAt,
+	}
+}
+
+func ToAccountDeletionResponse(deletion *domain.AccountDeletion) *AccountDeletionResponse {
+	return &AccountDeletionResponse{
+		ID:              deletion.ID,
+		UserID:          deletion.UserID,
+		Status:          deletion.Status,
+		Step:            deletion.Step,
+		Progress:        deletion.Progress(),
+		PostsDeleted:    deletion.PostsDeleted,
+		CommentsDeleted: deletion.CommentsDeleted,
+		LikesDeleted:    deletion.LikesDeleted,
+		FollowsDeleted:  deletion.FollowsDeleted,
+		RecordsDeleted:  deletion.RecordsDeleted,
+		Attempts:        deletion.Attempts,
+		Error:           deletion.Error,
+		CreatedAt:       deletion.CreatedAt,
+		UpdatedAt:       deletion.UpdatedAt,
+		CompletedAt:     deletion.CompletedAt,
+		StatusURL:       deletion.StatusURL,
+	}
+}
+
+func ToDataExportResponse(export *domain.DataExport) *DataExportResponse {
+	return &DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		IncludeHTML: export.IncludeHTML,
+		Size:        export.Size,
+		Error:       export.Error,
+		DownloadURL: export.DownloadURL,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
+
+func ToImportJobResponse(job *domain.ImportJob) *ImportJobResponse {
+	return &ImportJobResponse{
+		ID:               job.ID,
+		Source:           job.Source,
+		DryRun:           job.DryRun,
+		Status:           job.Status,
+		Stage:            job.Stage,
+		Line:             job.Line,
+		UsersImported:    job.UsersImported,
+		PostsImported:    job.PostsImported,
+		CommentsImported: job.CommentsImported,
+		LikesImported:    job.LikesImported,
+		FollowsImported:  job.FollowsImported,
+		Skipped:          job.Skipped,
+		ErrorCount:       job.ErrorCount,
+		Errors:           job.ErrorList(),
+		Error:            job.Error,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+		CompletedAt:      job.CompletedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountDeletionHandler struct {
+	deletionUsecase domain.AccountDeletionUsecase
+}
+
+func NewAccountDeletionHandler(router *gin.RouterGroup, deletionUsecase domain.AccountDeletionUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &AccountDeletionHandler{
+		deletionUsecase: deletionUsecase,
+	}
+
+	// Public routes, the signed link lets the deleted user follow the progress
+	router.GET("/account-deletions/:deletion_id/status", handler.GetDeletionStatus)
+
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.DELETE("/users", handler.DeleteAccount)
+	}
+
+	// The deleted user can no longer sign in, admins follow the progress
+	admin := router.Group("/admin/account-deletions")
+	admin.Use(authMiddleware.AuthRequired())
+	{
+		admin.GET("/:deletion_id", authMiddleware.RequirePermission(domain.PermissionDeleteAccounts), handler.GetDeletion)
+		admin.POST("/:deletion_id/retry", authMiddleware.RequirePermission(domain.PermissionDeleteAccounts), handler.RetryDeletion)
+	}
+}
+
+func (h *AccountDeletionHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	deletion, err := h.deletionUsecase.RequestDeletion(userID, req.Password, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.Response{
+		Success: true,
+		Message: "account deletion scheduled",
+		Data:    dto.ToAccountDeletionResponse(deletion),
+	})
+}
+
+func (h *AccountDeletionHandler) GetDeletion(c *gin.Context) {
+	deletionID, err := strconv.ParseUint(c.Param("deletion_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid account deletion id"})
+		return
+	}
+
+	deletion, err := h.deletionUsecase.GetDeletion(deletionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToAccountDeletionResponse(deletion),
+	})
+}
+
+func (h *AccountDeletionHandler) GetDeletionStatus(c *gin.Context) {
+	deletionID, err := strconv.ParseUint(c.Param("deletion_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid account deletion id"})
+		return
+	}
+
+	var query dto.DeletionStatusQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	deletion, err := h.deletionUsecase.GetDeletionStatus(deletionID, query.Expires, query.Signature)
+	if err != nil {
+		c.JSON(http.StatusForbidden, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToAccountDeletionResponse(deletion),
+	})
+}
+
+func (h *AccountDeletionHandler) RetryDeletion(c *gin.Context) {
+	deletionID, err := strconv.ParseUint(c.Param("deletion_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid account deletion id"})
+		return
+	}
+
+	deletion, err := h.deletionUsecase.RetryDeletion(deletionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "account deletion queued again",
+		Data:    dto.ToAccountDeletionResponse(deletion),
+	})
+}
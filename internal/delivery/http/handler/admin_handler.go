@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminUsecase domain.AdminUsecase
+}
+
+func NewAdminHandler(router *gin.RouterGroup, adminUsecase domain.AdminUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &AdminHandler{
+		adminUsecase: adminUsecase,
+	}
+
+	// All routes require authentication and a permission of the caller's role
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware.AuthRequired())
+	{
+		admin.GET("/users", authMiddleware.RequirePermission(domain.PermissionListUsers), handler.ListUsers)
+		admin.POST("/users/:user_id/suspension", authMiddleware.RequirePermission(domain.PermissionSuspendUsers), handler.SuspendUser)
+		admin.DELETE("/users/:user_id/suspension", authMiddleware.RequirePermission(domain.PermissionSuspendUsers), handler.UnsuspendUser)
+		admin.PUT("/users/:user_id/role", authMiddleware.RequirePermission(domain.PermissionManageRoles), handler.SetRole)
+		admin.DELETE("/posts/:post_id", authMiddleware.RequirePermission(domain.PermissionDeletePosts), handler.DeletePost)
+		admin.DELETE("/comments/:comment_id", authMiddleware.RequirePermission(domain.PermissionDeleteComments), handler.DeleteComment)
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	users, err := h.adminUsecase.ListUsers(pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	userResponses := make([]*dto.AdminUserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToAdminUserResponse(&user)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    userResponses,
+	})
+}
+
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	var req dto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	user, err := h.adminUsecase.SuspendUser(actorID, userID, req.Reason)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user suspended successfully",
+		Data:    dto.ToAdminUserResponse(user),
+	})
+}
+
+func (h *AdminHandler) UnsuspendUser(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	user, err := h.adminUsecase.UnsuspendUser(actorID, userID)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user unsuspended successfully",
+		Data:    dto.ToAdminUserResponse(user),
+	})
+}
+
+func (h *AdminHandler) SetRole(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	user, err := h.adminUsecase.SetRole(actorID, userID, req.Role)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "role updated successfully",
+		Data:    dto.ToAdminUserResponse(user),
+	})
+}
+
+func (h *AdminHandler) DeletePost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	if err := h.adminUsecase.DeletePost(postID); err != nil {
+		c.JSON(adminErrorStatus(err), dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "post deleted successfully",
+	})
+}
+
+func (h *AdminHandler) DeleteComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid comment id"})
+		return
+	}
+
+	if err := h.adminUsecase.DeleteComment(commentID); err != nil {
+		c.JSON(adminErrorStatus(err), dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "comment deleted successfully",
+	})
+}
+
+// adminErrorStatus maps the errors of administrative actions to a status code
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRole):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrPostNotFound), errors.Is(err, domain.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
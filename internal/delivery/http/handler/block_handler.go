@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	blockUsecase domain.BlockUsecase
+}
+
+func NewBlockHandler(router *gin.RouterGroup, blockUsecase domain.BlockUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &BlockHandler{
+		blockUsecase: blockUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/users/me")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("/blocks", handler.GetBlocked)
+		protected.POST("/blocks/:user_id", handler.Block)
+		protected.DELETE("/blocks/:user_id", handler.Unblock)
+		protected.GET("/mutes", handler.GetMuted)
+		protected.POST("/mutes/:user_id", handler.Mute)
+		protected.DELETE("/mutes/:user_id", handler.Unmute)
+	}
+}
+
+func (h *BlockHandler) GetBlocked(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	users, err := h.blockUsecase.GetBlocked(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	userResponses := make([]*dto.UserSummaryResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToUserSummaryResponse(&user)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    userResponses,
+	})
+}
+
+func (h *BlockHandler) Block(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	blockedID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.blockUsecase.Block(userID, blockedID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user blocked successfully",
+	})
+}
+
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	blockedID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.blockUsecase.Unblock(userID, blockedID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user unblocked successfully",
+	})
+}
+
+func (h *BlockHandler) GetMuted(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	users, err := h.blockUsecase.GetMuted(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	userResponses := make([]*dto.UserSummaryResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToUserSummaryResponse(&user)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    userResponses,
+	})
+}
+
+func (h *BlockHandler) Mute(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	mutedID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.blockUsecase.Mute(userID, mutedID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user muted successfully",
+	})
+}
+
+func (h *BlockHandler) Unmute(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	mutedID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.blockUsecase.Unmute(userID, mutedID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "user unmuted successfully",
+	})
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkHandler struct {
+	bookmarkUsecase domain.BookmarkUsecase
+}
+
+func NewBookmarkHandler(router *gin.RouterGroup, bookmarkUsecase domain.BookmarkUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &BookmarkHandler{
+		bookmarkUsecase: bookmarkUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.POST("/posts/:post_id/bookmarks", handler.Bookmark)
+		protected.DELETE("/posts/:post_id/bookmarks", handler.RemoveBookmark)
+		protected.GET("/users/me/bookmarks", handler.GetBookmarks)
+		protected.GET("/users/me/bookmarks/collections", handler.GetCollections)
+		protected.POST("/users/me/bookmarks/collections", handler.CreateCollection)
+		protected.DELETE("/users/me/bookmarks/collections/:collection_id", handler.DeleteCollection)
+	}
+}
+
+func (h *BookmarkHandler) Bookmark(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	// The body is optional; without it the bookmark is left uncollected
+	var req dto.BookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.bookmarkUsecase.Bookmark(userID, postID, req.CollectionID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "post bookmarked successfully",
+	})
+}
+
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	if err := h.bookmarkUsecase.RemoveBookmark(userID, postID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "bookmark removed successfully",
+	})
+}
+
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var query dto.BookmarkQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	page, err := h.bookmarkUsecase.GetBookmarks(userID, query.CollectionID, query.Cursor, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	bookmarked, err := h.bookmarkUsecase.GetBookmarkedPostIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	bookmarkResponses := make([]*dto.BookmarkResponse, len(page.Bookmarks))
+	for i, bookmark := range page.Bookmarks {
+		bookmarkResponses[i] = dto.ToBookmarkResponse(&bookmark)
+		if bookmarkResponses[i].Post != nil {
+			bookmarkResponses[i].Post.MarkBookmarked(bookmarked)
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.BookmarkPageResponse{
+			Bookmarks:  bookmarkResponses,
+			NextCursor: page.NextCursor,
+		},
+	})
+}
+
+func (h *BookmarkHandler) GetCollections(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	collections, err := h.bookmarkUsecase.GetCollections(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	collectionResponses := make([]*dto.BookmarkCollectionResponse, len(collections))
+	for i, collection := range collections {
+		collectionResponses[i] = dto.ToBookmarkCollectionResponse(&collection)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    collectionResponses,
+	})
+}
+
+func (h *BookmarkHandler) CreateCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateBookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	collection, err := h.bookmarkUsecase.CreateCollection(userID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "collection created successfully",
+		Data:    dto.ToBookmarkCollectionResponse(collection),
+	})
+}
+
+func (h *BookmarkHandler) DeleteCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("collection_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid collection id"})
+		return
+	}
+
+	if err := h.bookmarkUsecase.DeleteCollection(userID, collectionID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "collection deleted successfully",
+	})
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type CloseFriendHandler struct {
+	closeFriendUsecase domain.CloseFriendUsecase
+}
+
+func NewCloseFriendHandler(router *gin.RouterGroup, closeFriendUsecase domain.CloseFriendUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &CloseFriendHandler{
+		closeFriendUsecase: closeFriendUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/users/me/close-friends")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("", handler.GetCloseFriends)
+		protected.POST("/:user_id", handler.AddCloseFriend)
+		protected.DELETE("/:user_id", handler.RemoveCloseFriend)
+	}
+}
+
+func (h *CloseFriendHandler) GetCloseFriends(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	users, err := h.closeFriendUsecase.GetCloseFriends(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	userResponses := make([]*dto.UserSummaryResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToUserSummaryResponse(&user)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    userResponses,
+	})
+}
+
+func (h *CloseFriendHandler) AddCloseFriend(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	friendID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.closeFriendUsecase.AddCloseFriend(userID, friendID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "close friend added successfully",
+	})
+}
+
+func (h *CloseFriendHandler) RemoveCloseFriend(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	friendID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return
+	}
+
+	if err := h.closeFriendUsecase.RemoveCloseFriend(userID, friendID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "close friend removed successfully",
+	})
+}
@@ -27,6 +27,7 @@ func NewCommentHandler(router *gin.RouterGroup, commentUsecase domain.CommentUse
 		protected.POST("/posts/:post_id/comments", handler.CreateComment)
 		protected.PUT("/posts/:post_id/comments/:comment_id", handler.UpdateComment)
 		protected.DELETE("/posts/:post_id/comments/:comment_id", handler.DeleteComment)
+		protected.GET("/posts/:post_id/comments/:comment_id/revisions", handler.GetCommentRevisions)
 	}
 }
 
@@ -68,6 +69,12 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 }
 
 func (h *CommentHandler) GetPostComments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
 	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
@@ -80,7 +87,7 @@ func (h *CommentHandler) GetPostComments(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.commentUsecase.GetPostComments(postID, pagination.Page, pagination.Limit)
+	comments, err := h.commentUsecase.GetPostComments(postID, userID, pagination.Page, pagination.Limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
@@ -180,3 +187,46 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		Message: "comment deleted successfully",
 	})
 }
+
+func (h *CommentHandler) GetCommentRevisions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid comment id"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	revisions, err := h.commentUsecase.GetCommentRevisions(postID, commentID, userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	revisionResponses := make([]*dto.CommentRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		revisionResponses[i] = dto.ToCommentRevisionResponse(&revision)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    revisionResponses,
+	})
+}
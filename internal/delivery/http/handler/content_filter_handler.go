@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ContentFilterHandler struct {
+	contentFilterUsecase domain.ContentFilterUsecase
+}
+
+func NewContentFilterHandler(router *gin.RouterGroup, contentFilterUsecase domain.ContentFilterUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &ContentFilterHandler{
+		contentFilterUsecase: contentFilterUsecase,
+	}
+
+	// All routes require authentication and the moderation permission
+	moderation := router.Group("/admin/filter-decisions")
+	moderation.Use(authMiddleware.AuthRequired())
+	moderation.Use(authMiddleware.RequirePermission(domain.PermissionModerate))
+	{
+		moderation.GET("", handler.ListDecisions)
+	}
+}
+
+func (h *ContentFilterHandler) ListDecisions(c *gin.Context) {
+	var query dto.FilterDecisionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	decisions, err := h.contentFilterUsecase.ListDecisions(query.Decision, query.Page, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    decisions,
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportHandler struct {
+	exportUsecase domain.DataExportUsecase
+}
+
+func NewDataExportHandler(router *gin.RouterGroup, exportUsecase domain.DataExportUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &DataExportHandler{
+		exportUsecase: exportUsecase,
+	}
+
+	// Public routes, the signed link authorizes the download
+	router.GET("/exports/:export_id/download", handler.Download)
+
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.POST("/users/me/export", handler.RequestExport)
+		protected.GET("/users/me/exports/:export_id", handler.GetExport)
+	}
+}
+
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	// The body is optional; without it the export only contains JSON
+	var req dto.DataExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	export, err := h.exportUsecase.RequestExport(userID, req.IncludeHTML)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.Response{
+		Success: true,
+		Message: "data export scheduled",
+		Data:    dto.ToDataExportResponse(export),
+	})
+}
+
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	exportID, err := strconv.ParseUint(c.Param("export_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid export id"})
+		return
+	}
+
+	export, err := h.exportUsecase.GetExport(exportID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToDataExportResponse(export),
+	})
+}
+
+func (h *DataExportHandler) Download(c *gin.Context) {
+	exportID, err := strconv.ParseUint(c.Param("export_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid export id"})
+		return
+	}
+
+	var query dto.DownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	export, file, err := h.exportUsecase.OpenDownload(exportID, query.Expires, query.Signature)
+	if err != nil {
+		c.JSON(http.StatusForbidden, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, export.Size, "application/zip", file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="export-%d.zip"`, export.ID),
+	})
+}
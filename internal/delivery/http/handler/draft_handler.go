@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type DraftHandler struct {
+	postUsecase domain.PostUsecase
+}
+
+func NewDraftHandler(router *gin.RouterGroup, postUsecase domain.PostUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &DraftHandler{
+		postUsecase: postUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/users/me/drafts")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("", handler.GetDrafts)
+		protected.POST("", handler.CreateDraft)
+		protected.GET("/:post_id", handler.GetDraft)
+		protected.PUT("/:post_id", handler.UpdateDraft)
+		protected.DELETE("/:post_id", handler.DeleteDraft)
+		protected.POST("/:post_id/publish", handler.PublishDraft)
+	}
+}
+
+func (h *DraftHandler) GetDrafts(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	posts, err := h.postUsecase.GetDrafts(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	postResponses := make([]*dto.PostResponse, len(posts))
+	for i, post := range posts {
+		postResponses[i] = dto.ToPostResponse(&post)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    postResponses,
+	})
+}
+
+func (h *DraftHandler) CreateDraft(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.DraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	post := &domain.Post{
+		UserID:     userID,
+		Content:    req.Content,
+		ImageURL:   req.ImageURL,
+		Visibility: req.Visibility,
+		PublishAt:  req.PublishAt,
+	}
+
+	if err := h.postUsecase.CreateDraft(post); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "draft created successfully",
+		Data:    dto.ToPostResponse(post),
+	})
+}
+
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	post, err := h.postUsecase.GetDraft(postID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToPostResponse(post),
+	})
+}
+
+func (h *DraftHandler) UpdateDraft(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	var req dto.DraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	post := &domain.Post{
+		ID:         postID,
+		UserID:     userID,
+		Content:    req.Content,
+		ImageURL:   req.ImageURL,
+		Visibility: req.Visibility,
+		PublishAt:  req.PublishAt,
+	}
+
+	if err := h.postUsecase.UpdateDraft(post); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "draft updated successfully",
+		Data:    dto.ToPostResponse(post),
+	})
+}
+
+func (h *DraftHandler) DeleteDraft(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	if err := h.postUsecase.DeleteDraft(postID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "draft deleted successfully",
+	})
+}
+
+func (h *DraftHandler) PublishDraft(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	post, err := h.postUsecase.PublishDraft(postID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "draft published successfully",
+		Data:    dto.ToPostResponse(post),
+	})
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type FollowRequestHandler struct {
+	followRequestUsecase domain.FollowRequestUsecase
+}
+
+func NewFollowRequestHandler(router *gin.RouterGroup, followRequestUsecase domain.FollowRequestUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &FollowRequestHandler{
+		followRequestUsecase: followRequestUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/follow-requests")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("", handler.GetPending)
+		protected.POST("/:request_id/approve", handler.Approve)
+		protected.POST("/:request_id/reject", handler.Reject)
+	}
+}
+
+func (h *FollowRequestHandler) GetPending(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	requests, err := h.followRequestUsecase.GetPending(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	requestResponses := make([]*dto.FollowRequestResponse, len(requests))
+	for i, request := range requests {
+		requestResponses[i] = dto.ToFollowRequestResponse(&request)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    requestResponses,
+	})
+}
+
+func (h *FollowRequestHandler) Approve(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("request_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid request id"})
+		return
+	}
+
+	if err := h.followRequestUsecase.Approve(userID, requestID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "follow request approved",
+	})
+}
+
+func (h *FollowRequestHandler) Reject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("request_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid request id"})
+		return
+	}
+
+	if err := h.followRequestUsecase.Reject(userID, requestID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "follow request rejected",
+	})
+}
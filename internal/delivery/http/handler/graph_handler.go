@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type GraphHandler struct {
+	graphUsecase domain.GraphUsecase
+}
+
+func NewGraphHandler(router *gin.RouterGroup, graphUsecase domain.GraphUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &GraphHandler{
+		graphUsecase: graphUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/users/:user_id")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("/relationship/:other_user_id", handler.GetRelationship)
+		protected.GET("/mutual-friends/:other_user_id", handler.GetMutualFriends)
+	}
+}
+
+func (h *GraphHandler) GetRelationship(c *gin.Context) {
+	viewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	userID, otherID, ok := parseUserPair(c)
+	if !ok {
+		return
+	}
+
+	relationship, err := h.graphUsecase.GetRelationship(viewerID, userID, otherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToRelationshipResponse(relationship),
+	})
+}
+
+func (h *GraphHandler) GetMutualFriends(c *gin.Context) {
+	viewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	userID, otherID, ok := parseUserPair(c)
+	if !ok {
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	users, err := h.graphUsecase.GetMutualFriends(viewerID, userID, otherID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	userResponses := make([]*dto.UserSummaryResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToUserSummaryResponse(&user)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    userResponses,
+	})
+}
+
+// parseUserPair reads the user_id and other_user_id path parameters,
+// responding with 400 when one is invalid
+func parseUserPair(c *gin.Context) (uint64, uint64, bool) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return 0, 0, false
+	}
+
+	otherID, err := strconv.ParseUint(c.Param("other_user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
+		return 0, 0, false
+	}
+
+	return userID, otherID, true
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importUsecase domain.ImportUsecase
+}
+
+func NewImportHandler(router *gin.RouterGroup, importUsecase domain.ImportUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &ImportHandler{
+		importUsecase: importUsecase,
+	}
+
+	// All routes require authentication and the import permission
+	admin := router.Group("/admin/imports")
+	admin.Use(authMiddleware.AuthRequired())
+	{
+		admin.POST("", authMiddleware.RequirePermission(domain.PermissionImportData), handler.CreateImport)
+		admin.GET("/:import_id", authMiddleware.RequirePermission(domain.PermissionImportData), handler.GetImport)
+		admin.POST("/:import_id/resume", authMiddleware.RequirePermission(domain.PermissionImportData), handler.ResumeImport)
+	}
+}
+
+// CreateImport queues the NDJSON file sent as the request body
+func (h *ImportHandler) CreateImport(c *gin.Context) {
+	var query dto.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	job, err := h.importUsecase.CreateImport(query.Source, query.DryRun, c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.Response{
+		Success: true,
+		Message: "import scheduled",
+		Data:    dto.ToImportJobResponse(job),
+	})
+}
+
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	importID, err := strconv.ParseUint(c.Param("import_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid import id"})
+		return
+	}
+
+	job, err := h.importUsecase.GetImport(importID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToImportJobResponse(job),
+	})
+}
+
+func (h *ImportHandler) ResumeImport(c *gin.Context) {
+	importID, err := strconv.ParseUint(c.Param("import_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid import id"})
+		return
+	}
+
+	job, err := h.importUsecase.ResumeImport(importID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "import queued again",
+		Data:    dto.ToImportJobResponse(job),
+	})
+}
@@ -78,6 +78,12 @@ func (h *LikeHandler) UnlikePost(c *gin.Context) {
 }
 
 func (h *LikeHandler) GetPostLikes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
 	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
@@ -90,7 +96,7 @@ func (h *LikeHandler) GetPostLikes(c *gin.Context) {
 		return
 	}
 
-	likes, err := h.likeUsecase.GetPostLikes(postID, pagination.Page, pagination.Limit)
+	likes, err := h.likeUsecase.GetPostLikes(postID, userID, pagination.Page, pagination.Limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationUsecase domain.ModerationUsecase
+}
+
+func NewModerationHandler(router *gin.RouterGroup, moderationUsecase domain.ModerationUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &ModerationHandler{
+		moderationUsecase: moderationUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.POST("/posts/:post_id/reports", handler.ReportPost)
+		protected.POST("/posts/:post_id/comments/:comment_id/reports", handler.ReportComment)
+		protected.GET("/users/me/warnings", handler.GetWarnings)
+	}
+
+	// Moderation queue routes
+	moderation := router.Group("/admin/reports")
+	moderation.Use(authMiddleware.AuthRequired())
+	moderation.Use(authMiddleware.RequirePermission(domain.PermissionModerate))
+	{
+		moderation.GET("", handler.GetQueue)
+		moderation.GET("/:target_type/:target_id", handler.GetTargetReports)
+		moderation.POST("/:target_type/:target_id/actions", handler.TakeAction)
+	}
+}
+
+func (h *ModerationHandler) ReportPost(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	var req dto.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	report, err := h.moderationUsecase.ReportPost(userID, postID, req.Reason, req.Details)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "report submitted successfully",
+		Data:    dto.ToReportResponse(report),
+	})
+}
+
+func (h *ModerationHandler) ReportComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid comment id"})
+		return
+	}
+
+	var req dto.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	report, err := h.moderationUsecase.ReportComment(userID, postID, commentID, req.Reason, req.Details)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "report submitted successfully",
+		Data:    dto.ToReportResponse(report),
+	})
+}
+
+func (h *ModerationHandler) GetWarnings(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	warnings, err := h.moderationUsecase.GetWarnings(userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	warningResponses := make([]*dto.WarningResponse, len(warnings))
+	for i, warning := range warnings {
+		warningResponses[i] = dto.ToWarningResponse(&warning)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    warningResponses,
+	})
+}
+
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	items, err := h.moderationUsecase.GetQueue(pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    items,
+	})
+}
+
+func (h *ModerationHandler) GetTargetReports(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("target_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid target id"})
+		return
+	}
+
+	reports, err := h.moderationUsecase.GetTargetReports(c.Param("target_type"), targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	reportResponses := make([]*dto.ReportResponse, len(reports))
+	for i, report := range reports {
+		reportResponses[i] = dto.ToReportResponse(&report)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    reportResponses,
+	})
+}
+
+func (h *ModerationHandler) TakeAction(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("target_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid target id"})
+		return
+	}
+
+	var req dto.ModerationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	action, err := h.moderationUsecase.TakeAction(userID, c.Param("target_type"), targetID, req.Action, req.Note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "moderation action applied",
+		Data:    dto.ToModerationActionResponse(action),
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// oauthBindingCookie holds the value that ties a pending social login to the
+// browser that started it
+const oauthBindingCookie = "oauth_binding"
+
+type OAuthHandler struct {
+	oauthUsecase domain.OAuthUsecase
+}
+
+func NewOAuthHandler(router *gin.RouterGroup, oauthUsecase domain.OAuthUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &OAuthHandler{
+		oauthUsecase: oauthUsecase,
+	}
+
+	// Public routes
+	router.GET("/oauth/:provider/authorize", handler.Authorize)
+	router.GET("/oauth/:provider/callback", handler.Callback)
+
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("/users/me/identities", handler.GetIdentities)
+	}
+}
+
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	authURL, binding, err := h.oauthUsecase.AuthorizationURL(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Lax still sends the cookie on the top level redirect back from the provider
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthBindingCookie, binding, 0, "/", "", isSecureRequest(c), true)
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.OAuthAuthorizeResponse{AuthorizationURL: authURL},
+	})
+}
+
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	// The provider reports denied consent and other failures as query parameters
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "login failed: " + providerError})
+		return
+	}
+
+	var req dto.OAuthCallbackQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// The binding is single use like the state it belongs to
+	binding, _ := c.Cookie(oauthBindingCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthBindingCookie, "", -1, "/", "", isSecureRequest(c), true)
+
+	result, err := h.oauthUsecase.HandleCallback(c.Param("provider"), req.State, req.Code, binding)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if result.TwoFactorRequired {
+		c.JSON(http.StatusOK, dto.Response{
+			Success: true,
+			Message: "two-factor code required",
+			Data: dto.LoginResponse{
+				ChallengeToken:    result.ChallengeToken,
+				TwoFactorRequired: true,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.LoginResponse{Token: result.AccessToken},
+	})
+}
+
+func (h *OAuthHandler) GetIdentities(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	identities, err := h.oauthUsecase.GetIdentities(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	identityResponses := make([]*dto.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		identityResponses[i] = dto.ToIdentityResponse(&identity)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    identityResponses,
+	})
+}
+
+// isSecureRequest reports whether the client reached the server over https,
+// directly or through a TLS terminating proxy
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
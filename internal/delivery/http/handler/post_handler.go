@@ -11,12 +11,14 @@ import (
 )
 
 type PostHandler struct {
-	postUsecase domain.PostUsecase
+	postUsecase     domain.PostUsecase
+	bookmarkUsecase domain.BookmarkUsecase
 }
 
-func NewPostHandler(router *gin.RouterGroup, postUsecase domain.PostUsecase, authMiddleware *middleware.AuthMiddleware) {
+func NewPostHandler(router *gin.RouterGroup, postUsecase domain.PostUsecase, bookmarkUsecase domain.BookmarkUsecase, authMiddleware *middleware.AuthMiddleware) {
 	handler := &PostHandler{
-		postUsecase: postUsecase,
+		postUsecase:     postUsecase,
+		bookmarkUsecase: bookmarkUsecase,
 	}
 
 	// All routes require authentication
@@ -27,8 +29,12 @@ func NewPostHandler(router *gin.RouterGroup, postUsecase domain.PostUsecase, aut
 		protected.POST("/posts", handler.CreatePost)
 		protected.PUT("/posts/:post_id", handler.UpdatePost)
 		protected.DELETE("/posts/:post_id", handler.DeletePost)
+		protected.POST("/posts/:post_id/restore", handler.RestorePost)
 		protected.GET("/friends/:user_id/posts", handler.GetUserPosts)
 		protected.GET("/users/:user_id/newsfeed", handler.GetNewsFeed)
+		protected.POST("/posts/:post_id/shares", handler.SharePost)
+		protected.DELETE("/posts/:post_id/shares", handler.UndoRepost)
+		protected.GET("/posts/:post_id/revisions", handler.GetPostRevisions)
 	}
 }
 
@@ -46,9 +52,10 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	}
 
 	post := &domain.Post{
-		UserID:   userID,
-		Content:  req.Content,
-		ImageURL: req.ImageURL,
+		UserID:     userID,
+		Content:    req.Content,
+		ImageURL:   req.ImageURL,
+		Visibility: req.Visibility,
 	}
 
 	if err := h.postUsecase.CreatePost(post); err != nil {
@@ -64,13 +71,19 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 }
 
 func (h *PostHandler) GetPost(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
 	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
 		return
 	}
 
-	post, err := h.postUsecase.GetPost(postID)
+	post, err := h.postUsecase.GetPost(postID, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
@@ -80,9 +93,12 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
+	response := dto.ToPostResponse(post)
+	h.markBookmarked(userID, response)
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Data:    dto.ToPostResponse(post),
+		Data:    response,
 	})
 }
 
@@ -106,10 +122,11 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	}
 
 	post := &domain.Post{
-		ID:       postID,
-		UserID:   userID,
-		Content:  req.Content,
-		ImageURL: req.ImageURL,
+		ID:         postID,
+		UserID:     userID,
+		Content:    req.Content,
+		ImageURL:   req.ImageURL,
+		Visibility: req.Visibility,
 	}
 
 	if err := h.postUsecase.UpdatePost(post); err != nil {
@@ -117,10 +134,13 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	response := dto.ToPostResponse(post)
+	h.markBookmarked(userID, response)
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "post updated successfully",
-		Data:    dto.ToPostResponse(post),
+		Data:    response,
 	})
 }
 
@@ -138,7 +158,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	}
 
 	// First get the post to check ownership
-	post, err := h.postUsecase.GetPost(postID)
+	post, err := h.postUsecase.GetPost(postID, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
@@ -163,7 +183,46 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	})
 }
 
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	post, err := h.postUsecase.RestorePost(postID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+	if post == nil {
+		c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: "post not found"})
+		return
+	}
+
+	response := dto.ToPostResponse(post)
+	h.markBookmarked(userID, response)
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "post restored successfully",
+		Data:    response,
+	})
+}
+
 func (h *PostHandler) GetUserPosts(c *gin.Context) {
+	viewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
 	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
@@ -176,7 +235,7 @@ func (h *PostHandler) GetUserPosts(c *gin.Context) {
 		return
 	}
 
-	posts, err := h.postUsecase.GetUserPosts(userID, pagination.Page, pagination.Limit)
+	posts, err := h.postUsecase.GetUserPosts(userID, viewerID, pagination.Page, pagination.Limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
@@ -187,6 +246,7 @@ func (h *PostHandler) GetUserPosts(c *gin.Context) {
 	for i, post := range posts {
 		postResponses[i] = dto.ToPostResponse(&post)
 	}
+	h.markBookmarked(viewerID, postResponses...)
 
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
@@ -218,9 +278,118 @@ func (h *PostHandler) GetNewsFeed(c *gin.Context) {
 	for i, post := range posts {
 		postResponses[i] = dto.ToPostResponse(&post)
 	}
+	h.markBookmarked(userID, postResponses...)
 
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Data:    postResponses,
 	})
 }
+
+func (h *PostHandler) SharePost(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	var req dto.SharePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	post, err := h.postUsecase.SharePost(userID, postID, req.Content, req.Visibility)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	response := dto.ToPostResponse(post)
+	h.markBookmarked(userID, response)
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "post shared successfully",
+		Data:    response,
+	})
+}
+
+func (h *PostHandler) UndoRepost(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	if err := h.postUsecase.UndoRepost(userID, postID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "repost removed successfully",
+	})
+}
+
+func (h *PostHandler) GetPostRevisions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid post id"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	revisions, err := h.postUsecase.GetPostRevisions(postID, userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	revisionResponses := make([]*dto.PostRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		revisionResponses[i] = dto.ToPostRevisionResponse(&revision)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    revisionResponses,
+	})
+}
+
+// markBookmarked sets bookmarked_by_me for the viewer. The flag stays false
+// when the viewer's bookmarks cannot be loaded.
+func (h *PostHandler) markBookmarked(viewerID uint64, responses ...*dto.PostResponse) {
+	bookmarked, err := h.bookmarkUsecase.GetBookmarkedPostIDs(viewerID)
+	if err != nil {
+		return
+	}
+	for _, response := range responses {
+		response.MarkBookmarked(bookmarked)
+	}
+}
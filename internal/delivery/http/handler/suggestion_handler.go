@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestionHandler struct {
+	suggestionUsecase domain.SuggestionUsecase
+}
+
+func NewSuggestionHandler(router *gin.RouterGroup, suggestionUsecase domain.SuggestionUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &SuggestionHandler{
+		suggestionUsecase: suggestionUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("/users/me/suggestions")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("", handler.GetSuggestions)
+	}
+}
+
+func (h *SuggestionHandler) GetSuggestions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var query dto.SuggestionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	suggestions, err := h.suggestionUsecase.GetSuggestions(userID, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	suggestionResponses := make([]*dto.SuggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		suggestionResponses[i] = dto.ToSuggestionResponse(&suggestion)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    suggestionResponses,
+	})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUsecase domain.TwoFactorUsecase
+}
+
+func NewTwoFactorHandler(router *gin.RouterGroup, twoFactorUsecase domain.TwoFactorUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &TwoFactorHandler{
+		twoFactorUsecase: twoFactorUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.POST("/users/me/2fa", handler.Enroll)
+		protected.POST("/users/me/2fa/confirm", handler.Confirm)
+		protected.DELETE("/users/me/2fa", handler.Disable)
+	}
+}
+
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	enrollment, err := h.twoFactorUsecase.Enroll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "scan the otpauth uri and confirm a code to enable two-factor authentication",
+		Data:    enrollment,
+	})
+}
+
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.ConfirmTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.twoFactorUsecase.Confirm(userID, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "two-factor authentication enabled",
+	})
+}
+
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.twoFactorUsecase.Disable(userID, req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "two-factor authentication disabled",
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,12 +12,14 @@ import (
 )
 
 type UserHandler struct {
-	userUsecase domain.UserUsecase
+	userUsecase    domain.UserUsecase
+	privacyUsecase domain.PrivacyUsecase
 }
 
-func NewUserHandler(router *gin.RouterGroup, userUsecase domain.UserUsecase, authMiddleware *middleware.AuthMiddleware) {
+func NewUserHandler(router *gin.RouterGroup, userUsecase domain.UserUsecase, privacyUsecase domain.PrivacyUsecase, authMiddleware *middleware.AuthMiddleware) {
 	handler := &UserHandler{
-		userUsecase: userUsecase,
+		userUsecase:    userUsecase,
+		privacyUsecase: privacyUsecase,
 	}
 
 	// Public routes
@@ -28,8 +31,20 @@ func NewUserHandler(router *gin.RouterGroup, userUsecase domain.UserUsecase, aut
 	protected.Use(authMiddleware.AuthRequired())
 	{
 		protected.GET("/users/:user_id", handler.GetProfile)
+		protected.GET("/users/by-username/:username", handler.GetProfileByUsername)
+		protected.PUT("/users/me/username", handler.ChangeUsername)
 		protected.PUT("/users", handler.UpdateProfile)
-		protected.DELETE("/users", handler.DeleteProfile)
+		protected.PATCH("/users", handler.UpdateProfile)
+		protected.PUT("/users/me/password", handler.ChangePassword)
+		protected.PUT("/users/me/avatar", handler.SetAvatar)
+		protected.DELETE("/users/me/avatar", handler.DeleteAvatar)
+		protected.PUT("/users/me/cover", handler.SetCover)
+		protected.DELETE("/users/me/cover", handler.DeleteCover)
+		protected.PUT("/users/me/private", handler.SetPrivate)
+		protected.GET("/users/me/privacy", handler.GetPrivacySettings)
+		protected.PUT("/users/me/privacy", handler.UpdatePrivacySettings)
+		protected.GET("/users/:user_id/followers", handler.GetFollowers)
+		protected.GET("/users/:user_id/following", handler.GetFollowing)
 		protected.GET("/friends/:user_id", handler.GetFollowers)
 		protected.POST("/friends/:user_id", handler.Follow)
 		protected.DELETE("/friends/:user_id", handler.Unfollow)
@@ -71,15 +86,34 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.userUsecase.Login(req.Username, req.Password)
+	// The second step of a two-factor login sends the challenge token instead of credentials
+	var result *domain.LoginResult
+	var err error
+	if req.ChallengeToken != "" {
+		result, err = h.userUsecase.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, c.ClientIP())
+	} else {
+		result, err = h.userUsecase.Login(req.Username, req.Password, req.Code, c.ClientIP())
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: err.Error()})
 		return
 	}
 
+	if result.TwoFactorRequired {
+		c.JSON(http.StatusOK, dto.Response{
+			Success: true,
+			Message: "two-factor code required",
+			Data: dto.LoginResponse{
+				ChallengeToken:    result.ChallengeToken,
+				TwoFactorRequired: true,
+			},
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Data:    dto.LoginResponse{Token: token},
+		Data:    dto.LoginResponse{Token: result.AccessToken},
 	})
 }
 
@@ -96,12 +130,135 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	h.respondProfile(c, user)
+}
+
+// GetProfileByUsername looks a user up by their username or by a former
+// username that is still reserved for them
+func (h *UserHandler) GetProfileByUsername(c *gin.Context) {
+	user, err := h.userUsecase.GetByUsername(c.Param("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	h.respondProfile(c, user)
+}
+
+// respondProfile sends the profile page of user with the fields the viewer
+// is allowed to see
+func (h *UserHandler) respondProfile(c *gin.Context, user *domain.User) {
+	viewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	access, err := h.privacyUsecase.GetProfileAccess(viewerID, user.ID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	stats, err := h.userUsecase.GetProfileStats(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
+		Data:    dto.ToProfileResponse(user, stats, access),
+	})
+}
+
+func (h *UserHandler) GetPrivacySettings(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	settings, err := h.privacyUsecase.GetSettings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToPrivacySettingsResponse(settings),
+	})
+}
+
+// UpdatePrivacySettings changes who can see the profile fields sent and keeps
+// the omitted ones
+func (h *UserHandler) UpdatePrivacySettings(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.UpdatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	update := &domain.PrivacyUpdate{
+		Email:    req.Email,
+		Birthday: req.Birthday,
+		Location: req.Location,
+		Website:  req.Website,
+		Pronouns: req.Pronouns,
+	}
+
+	settings, err := h.privacyUsecase.UpdateSettings(userID, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "privacy settings updated successfully",
+		Data:    dto.ToPrivacySettingsResponse(settings),
+	})
+}
+
+func (h *UserHandler) ChangeUsername(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	user, err := h.userUsecase.ChangeUsername(userID, req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "username changed successfully",
 		Data:    dto.ToUserResponse(user),
 	})
 }
 
+// UpdateProfile changes the fields sent and keeps the omitted ones, for PUT
+// and PATCH alike
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
@@ -115,15 +272,18 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	user := &domain.User{
-		ID:        userID,
+	update := &domain.ProfileUpdate{
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Birthday:  req.Birthday,
-		Password:  req.Password,
+		Bio:       req.Bio,
+		Website:   req.Website,
+		Location:  req.Location,
+		Pronouns:  req.Pronouns,
 	}
 
-	if err := h.userUsecase.UpdateProfile(user); err != nil {
+	user, err := h.userUsecase.UpdateProfile(userID, update)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
 	}
@@ -131,24 +291,125 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "profile updated successfully",
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// ChangePassword sets a new password after checking the current one
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.userUsecase.ChangePassword(userID, req.CurrentPassword, req.NewPassword, req.Code); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "password changed successfully",
+	})
+}
+
+func (h *UserHandler) SetAvatar(c *gin.Context) {
+	h.setProfileImage(c, domain.ProfileImageAvatar)
+}
+
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	h.deleteProfileImage(c, domain.ProfileImageAvatar)
+}
+
+func (h *UserHandler) SetCover(c *gin.Context) {
+	h.setProfileImage(c, domain.ProfileImageCover)
+}
+
+func (h *UserHandler) DeleteCover(c *gin.Context) {
+	h.deleteProfileImage(c, domain.ProfileImageCover)
+}
+
+// setProfileImage replaces a profile image with the multipart "image" file
+func (h *UserHandler) setProfileImage(c *gin.Context, kind string) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "image file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	user, err := h.userUsecase.SetProfileImage(userID, kind, file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: kind + " updated successfully",
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+func (h *UserHandler) deleteProfileImage(c *gin.Context, kind string) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	user, err := h.userUsecase.DeleteProfileImage(userID, kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: kind + " removed successfully",
+		Data:    dto.ToUserResponse(user),
 	})
 }
 
-func (h *UserHandler) DeleteProfile(c *gin.Context) {
+func (h *UserHandler) SetPrivate(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
 		return
 	}
 
-	if err := h.userUsecase.DeleteProfile(userID); err != nil {
+	var req dto.SetPrivateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.userUsecase.SetPrivate(userID, *req.IsPrivate); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "profile deleted successfully",
+		Message: "account privacy updated successfully",
 	})
 }
 
@@ -165,14 +426,21 @@ func (h *UserHandler) Follow(c *gin.Context) {
 		return
 	}
 
-	if err := h.userUsecase.Follow(followerID, followingID); err != nil {
+	status, err := h.userUsecase.Follow(followerID, followingID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
 	}
 
+	message := "followed successfully"
+	if status == domain.FollowStatusRequested {
+		message = "follow request sent"
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "followed successfully",
+		Message: message,
+		Data:    dto.FollowResponse{Status: status},
 	})
 }
 
@@ -201,26 +469,47 @@ func (h *UserHandler) Unfollow(c *gin.Context) {
 }
 
 func (h *UserHandler) GetFollowers(c *gin.Context) {
+	h.getFollowList(c, h.userUsecase.GetFollowers)
+}
+
+func (h *UserHandler) GetFollowing(c *gin.Context) {
+	h.getFollowList(c, h.userUsecase.GetFollowing)
+}
+
+// getFollowList serves a page of a followers or following list
+func (h *UserHandler) getFollowList(c *gin.Context, list func(userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error)) {
+	viewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
 	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid user id"})
 		return
 	}
 
-	followers, err := h.userUsecase.GetFollowers(userID)
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	entries, err := list(userID, viewerID, pagination.Page, pagination.Limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
 		return
 	}
 
 	// Convert to response DTOs
-	followerResponses := make([]*dto.UserResponse, len(followers))
-	for i, follower := range followers {
-		followerResponses[i] = dto.ToUserResponse(&follower)
+	userResponses := make([]*dto.FollowUserResponse, len(entries))
+	for i, entry := range entries {
+		userResponses[i] = dto.ToFollowUserResponse(&entry)
 	}
 
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Data:    followerResponses,
+		Data:    userResponses,
 	})
 }
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationHandler struct {
+	verificationUsecase domain.VerificationUsecase
+}
+
+func NewVerificationHandler(router *gin.RouterGroup, verificationUsecase domain.VerificationUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &VerificationHandler{
+		verificationUsecase: verificationUsecase,
+	}
+
+	// Public routes
+	router.POST("/users/verify", handler.VerifyEmail)
+	router.POST("/password/forgot", handler.ForgotPassword)
+	router.POST("/password/reset", handler.ResetPassword)
+	router.POST("/users/email/confirm", handler.ConfirmEmailChange)
+
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.POST("/users/verify/resend", handler.ResendVerification)
+		protected.POST("/users/me/email", handler.ChangeEmail)
+	}
+}
+
+func (h *VerificationHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.verificationUsecase.VerifyEmail(req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "email verified successfully",
+	})
+}
+
+func (h *VerificationHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	if err := h.verificationUsecase.SendEmailVerification(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "verification email sent",
+	})
+}
+
+func (h *VerificationHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.verificationUsecase.ForgotPassword(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Same response whether or not the email exists
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "if the email is registered, a reset link has been sent",
+	})
+}
+
+func (h *VerificationHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.verificationUsecase.ResetPassword(req.Token, req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "password reset successfully",
+	})
+}
+
+// ChangeEmail sends a confirmation link to the new address, the email of the
+// account changes once it is opened
+func (h *VerificationHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.verificationUsecase.RequestEmailChange(userID, req.Email, req.Password, req.Code); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "confirmation email sent to the new address",
+	})
+}
+
+func (h *VerificationHandler) ConfirmEmailChange(c *gin.Context) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if err := h.verificationUsecase.ConfirmEmailChange(req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "email changed successfully",
+	})
+}
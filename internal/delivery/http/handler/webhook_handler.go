@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/dto"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookUsecase domain.WebhookUsecase
+}
+
+func NewWebhookHandler(router *gin.RouterGroup, webhookUsecase domain.WebhookUsecase, authMiddleware *middleware.AuthMiddleware) {
+	handler := &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+
+	// All routes require authentication
+	protected := router.Group("")
+	protected.Use(authMiddleware.AuthRequired())
+	{
+		protected.GET("/webhooks", handler.GetWebhooks)
+		protected.POST("/webhooks", handler.CreateWebhook)
+		protected.GET("/webhooks/:webhook_id", handler.GetWebhook)
+		protected.PUT("/webhooks/:webhook_id", handler.UpdateWebhook)
+		protected.DELETE("/webhooks/:webhook_id", handler.DeleteWebhook)
+		protected.GET("/webhooks/:webhook_id/deliveries", handler.GetDeliveries)
+		protected.POST("/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	webhook := &domain.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: req.Secret,
+	}
+	webhook.SetEvents(req.Events)
+
+	if err := h.webhookUsecase.CreateWebhook(webhook); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "webhook created successfully",
+		Data:    dto.ToWebhookResponse(webhook),
+	})
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhooks, err := h.webhookUsecase.GetUserWebhooks(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	webhookResponses := make([]*dto.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		webhookResponses[i] = dto.ToWebhookResponse(&webhook)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    webhookResponses,
+	})
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid webhook id"})
+		return
+	}
+
+	webhook, err := h.webhookUsecase.GetWebhook(webhookID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.ToWebhookResponse(webhook),
+	})
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid webhook id"})
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// First get the webhook so omitted fields keep their current values
+	webhook, err := h.webhookUsecase.GetWebhook(webhookID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	if req.URL != "" {
+		webhook.URL = req.URL
+	}
+	if len(req.Events) > 0 {
+		webhook.SetEvents(req.Events)
+	}
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := h.webhookUsecase.UpdateWebhook(webhook); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "webhook updated successfully",
+		Data:    dto.ToWebhookResponse(webhook),
+	})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid webhook id"})
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(webhookID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "webhook deleted successfully",
+	})
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid webhook id"})
+		return
+	}
+
+	var pagination dto.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	deliveries, err := h.webhookUsecase.GetDeliveries(webhookID, userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	// Convert to response DTOs
+	deliveryResponses := make([]*dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveryResponses[i] = dto.ToWebhookDeliveryResponse(&delivery)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    deliveryResponses,
+	})
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "unauthorized"})
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid webhook id"})
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "invalid delivery id"})
+		return
+	}
+
+	delivery, err := h.webhookUsecase.Redeliver(webhookID, deliveryID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.Response{
+		Success: true,
+		Message: "redelivery scheduled",
+		Data:    dto.ToWebhookDeliveryResponse(delivery),
+	})
+}
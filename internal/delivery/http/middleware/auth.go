@@ -4,17 +4,28 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// AccountLoader loads the current account of an authenticated user
+type AccountLoader interface {
+	GetProfile(id uint64) (*domain.User, error)
+}
+
 type AuthMiddleware struct {
 	jwtSecret string
+	accounts  AccountLoader
 }
 
-func NewAuthMiddleware(secret string) *AuthMiddleware {
+// NewAuthMiddleware creates the auth middleware. Access tokens of suspended
+// users are rejected even before they expire, and role changes apply to
+// tokens issued before them.
+func NewAuthMiddleware(secret string, accounts AccountLoader) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtSecret: secret,
+		accounts:  accounts,
 	}
 }
 
@@ -49,15 +60,58 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		// Only access tokens grant access, not e.g. two-factor challenge tokens
+		if typ, _ := claims["typ"].(string); typ != "access" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token type"})
+			c.Abort()
+			return
+		}
+
 		// Set user ID from claims
-		if userID, ok := claims["user_id"].(float64); ok {
-			c.Set("user_id", uint64(userID))
-		} else {
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
 			c.Abort()
 			return
 		}
 
+		user, err := m.accounts.GetProfile(uint64(userID))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.Abort()
+			return
+		}
+		if user.IsSuspended() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "account suspended"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+
+		// The role comes from the account rather than the token, so a demoted
+		// user loses their permissions before the token expires
+		role := user.Role
+		if role == "" {
+			role = domain.RoleUser
+		}
+		c.Set("role", role)
+
+		c.Next()
+	}
+}
+
+// RequirePermission only lets through users whose role grants permission. It
+// must run after AuthRequired.
+func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := GetRole(c)
+		if !exists || !domain.HasPermission(role, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -70,3 +124,12 @@ func GetUserID(c *gin.Context) (uint64, bool) {
 	}
 	return userID.(uint64), true
 }
+
+// GetRole gets the authenticated user role from the context
+func GetRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+	return role.(string), true
+}
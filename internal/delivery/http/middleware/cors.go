@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -89,7 +90,7 @@ func CORS(config *CORSConfig) gin.HandlerFunc {
 			c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowMethods, ","))
 			c.Header("Access-Control-Allow-Headers", strings.Join(config.AllowHeaders, ","))
 			if config.MaxAge > 0 {
-				c.Header("Access-Control-Max-Age", string(config.MaxAge))
+				c.Header("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
 			}
 			c.AbortWithStatus(http.StatusNoContent)
 			return
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -94,7 +95,7 @@ func (rl *RateLimiter) RateLimitByUser() gin.HandlerFunc {
 			}
 		} else {
 			// Use user ID for rate limiting
-			key := string(userID)
+			key := strconv.FormatUint(userID, 10)
 			limiter := rl.getLimiter(key)
 
 			if !limiter.Allow() {
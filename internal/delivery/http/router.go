@@ -1,8 +1,6 @@
 package http
 
 import (
-	"time"
-
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/handler"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/delivery/http/middleware"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
@@ -13,15 +11,32 @@ import (
 
 // RouterConfig holds configuration for the router
 type RouterConfig struct {
-	UserUsecase    domain.UserUsecase
-	PostUsecase    domain.PostUsecase
-	CommentUsecase domain.CommentUsecase
-	LikeUsecase    domain.LikeUsecase
-	Logger         *logrus.Logger
-	JWTSecret      string
-	AllowOrigins   []string
-	RateLimit      float64
-	RateBurst      int
+	UserUsecase            domain.UserUsecase
+	PostUsecase            domain.PostUsecase
+	CommentUsecase         domain.CommentUsecase
+	LikeUsecase            domain.LikeUsecase
+	WebhookUsecase         domain.WebhookUsecase
+	VerificationUsecase    domain.VerificationUsecase
+	TwoFactorUsecase       domain.TwoFactorUsecase
+	OAuthUsecase           domain.OAuthUsecase
+	AdminUsecase           domain.AdminUsecase
+	ModerationUsecase      domain.ModerationUsecase
+	ContentFilterUsecase   domain.ContentFilterUsecase
+	BlockUsecase           domain.BlockUsecase
+	FollowRequestUsecase   domain.FollowRequestUsecase
+	CloseFriendUsecase     domain.CloseFriendUsecase
+	BookmarkUsecase        domain.BookmarkUsecase
+	AccountDeletionUsecase domain.AccountDeletionUsecase
+	DataExportUsecase      domain.DataExportUsecase
+	ImportUsecase          domain.ImportUsecase
+	SuggestionUsecase      domain.SuggestionUsecase
+	GraphUsecase           domain.GraphUsecase
+	PrivacyUsecase         domain.PrivacyUsecase
+	Logger                 *logrus.Logger
+	JWTSecret              string
+	AllowOrigins           []string
+	RateLimit              float64
+	RateBurst              int
 }
 
 // SetupRouter sets up the HTTP router with all handlers and middleware
@@ -45,7 +60,7 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 	rateLimiter := middleware.NewRateLimiter(rate.Limit(config.RateLimit), config.RateBurst)
 
 	// Create auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(config.JWTSecret)
+	authMiddleware := middleware.NewAuthMiddleware(config.JWTSecret, config.UserUsecase)
 
 	// API v1 routes
 	v1 := router.Group("/v1")
@@ -55,7 +70,10 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 		public.Use(rateLimiter.RateLimit())
 		{
 			// User registration and login
-			handler.NewUserHandler(public, config.UserUsecase, authMiddleware)
+			handler.NewUserHandler(public, config.UserUsecase, config.PrivacyUsecase, authMiddleware)
+			handler.NewVerificationHandler(public, config.VerificationUsecase, authMiddleware)
+			handler.NewOAuthHandler(public, config.OAuthUsecase, authMiddleware)
+			handler.NewDataExportHandler(public, config.DataExportUsecase, authMiddleware)
 		}
 
 		// Protected routes with user-based rate limiting
@@ -64,9 +82,23 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 		protected.Use(rateLimiter.RateLimitByUser())
 		{
 			// Initialize handlers
-			handler.NewPostHandler(protected, config.PostUsecase, authMiddleware)
+			handler.NewPostHandler(protected, config.PostUsecase, config.BookmarkUsecase, authMiddleware)
 			handler.NewCommentHandler(protected, config.CommentUsecase, authMiddleware)
 			handler.NewLikeHandler(protected, config.LikeUsecase, authMiddleware)
+			handler.NewWebhookHandler(protected, config.WebhookUsecase, authMiddleware)
+			handler.NewTwoFactorHandler(protected, config.TwoFactorUsecase, authMiddleware)
+			handler.NewAdminHandler(protected, config.AdminUsecase, authMiddleware)
+			handler.NewModerationHandler(protected, config.ModerationUsecase, authMiddleware)
+			handler.NewContentFilterHandler(protected, config.ContentFilterUsecase, authMiddleware)
+			handler.NewBlockHandler(protected, config.BlockUsecase, authMiddleware)
+			handler.NewFollowRequestHandler(protected, config.FollowRequestUsecase, authMiddleware)
+			handler.NewCloseFriendHandler(protected, config.CloseFriendUsecase, authMiddleware)
+			handler.NewBookmarkHandler(protected, config.BookmarkUsecase, authMiddleware)
+			handler.NewDraftHandler(protected, config.PostUsecase, authMiddleware)
+			handler.NewAccountDeletionHandler(protected, config.AccountDeletionUsecase, authMiddleware)
+			handler.NewImportHandler(protected, config.ImportUsecase, authMiddleware)
+			handler.NewSuggestionHandler(protected, config.SuggestionUsecase, authMiddleware)
+			handler.NewGraphHandler(protected, config.GraphUsecase, authMiddleware)
 		}
 	}
 
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"time"
+)
+
+// Account deletion statuses
+const (
+	AccountDeletionPending   = "pending"
+	AccountDeletionRunning   = "running"
+	AccountDeletionCompleted = "completed"
+	AccountDeletionFailed    = "failed"
+)
+
+// Account deletion steps, in the order they run
+const (
+	AccountDeletionStepPosts       = "posts"
+	AccountDeletionStepComments    = "comments"
+	AccountDeletionStepLikes       = "likes"
+	AccountDeletionStepFollows     = "follows"
+	AccountDeletionStepAccountData = "account_data"
+	AccountDeletionStepUser        = "user"
+	AccountDeletionStepDone        = "done"
+)
+
+// AccountDeletionSteps lists the steps of an account deletion in order
+var AccountDeletionSteps = []string{
+	AccountDeletionStepPosts,
+	AccountDeletionStepComments,
+	AccountDeletionStepLikes,
+	AccountDeletionStepFollows,
+	AccountDeletionStepAccountData,
+	AccountDeletionStepUser,
+}
+
+// AccountDeletion tracks the removal of a deleted user's content and
+// relationships. The account is soft deleted right away, a worker then
+// removes everything else step by step in batches.
+type AccountDeletion struct {
+	ID              uint64     `json:"id" gorm:"primaryKey"`
+	UserID          uint64     `json:"user_id" gorm:"not null;uniqueIndex"`
+	Status          string     `json:"status" gorm:"not null;default:pending;index"`
+	Step            string     `json:"step" gorm:"not null"`
+	PostsDeleted    int64      `json:"posts_deleted" gorm:"not null;default:0"`
+	CommentsDeleted int64      `json:"comments_deleted" gorm:"not null;default:0"`
+	LikesDeleted    int64      `json:"likes_deleted" gorm:"not null;default:0"`
+	FollowsDeleted  int64      `json:"follows_deleted" gorm:"not null;default:0"`
+	RecordsDeleted  int64      `json:"records_deleted" gorm:"not null;default:0"`
+	Attempts        int        `json:"attempts" gorm:"not null;default:0"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	// StatusURL is the signed link the deleted user can follow the progress
+	// through, it is not stored
+	StatusURL string `json:"status_url,omitempty" gorm:"-"`
+}
+
+// Progress returns the share of completed steps as a percentage
+func (d *AccountDeletion) Progress() int {
+	if d.Status == AccountDeletionCompleted {
+		return 100
+	}
+	for i, step := range AccountDeletionSteps {
+		if step == d.Step {
+			return i * 100 / len(AccountDeletionSteps)
+		}
+	}
+	return 0
+}
+
+// NextAccountDeletionStep returns the step running after step
+func NextAccountDeletionStep(step string) string {
+	for i, s := range AccountDeletionSteps {
+		if s == step && i+1 < len(AccountDeletionSteps) {
+			return AccountDeletionSteps[i+1]
+		}
+	}
+	return AccountDeletionStepDone
+}
+
+type AccountDeletionRepository interface {
+	// Create queues a deletion and soft deletes its user in one transaction
+	Create(deletion *AccountDeletion) error
+	GetByID(id uint64) (*AccountDeletion, error)
+	Update(deletion *AccountDeletion) error
+	Claim(staleBefore time.Time) (*AccountDeletion, error)
+	GetPosts(userID uint64, limit int) ([]Post, error)
+	GetComments(userID uint64, limit int) ([]Comment, error)
+	DeleteLikes(userID uint64, limit int) ([]Like, error)
+	DeleteFollows(userID uint64, limit int) ([]Follow, error)
+	DeleteAccountData(userID uint64) (int64, error)
+}
+
+type AccountDeletionUsecase interface {
+	RequestDeletion(userID uint64, password, code string) (*AccountDeletion, error)
+	GetDeletion(id uint64) (*AccountDeletion, error)
+	GetDeletionStatus(id uint64, expires int64, signature string) (*AccountDeletion, error)
+	RetryDeletion(id uint64) (*AccountDeletion, error)
+	ProcessNext(batchSize int) (bool, error)
+}
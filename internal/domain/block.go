@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+)
+
+// Block hides two users from each other. Blocking removes the follows in both
+// directions and stops the users from following, commenting on or liking each other.
+type Block struct {
+	BlockerID uint64    `json:"blocker_id" gorm:"primaryKey"`
+	BlockedID uint64    `json:"blocked_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Mute hides a user's posts from the muter's newsfeed without unfollowing
+type Mute struct {
+	MuterID   uint64    `json:"muter_id" gorm:"primaryKey"`
+	MutedID   uint64    `json:"muted_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BlockRepository interface {
+	Block(block *Block) error
+	Unblock(blockerID, blockedID uint64) error
+	IsBlocked(userID, otherID uint64) (bool, error)
+	GetBlockedIDs(userID uint64) ([]uint64, error)
+	GetBlocked(userID uint64, page, limit int) ([]User, error)
+	Mute(mute *Mute) error
+	Unmute(muterID, mutedID uint64) error
+	GetMuted(userID uint64, page, limit int) ([]User, error)
+}
+
+type BlockUsecase interface {
+	Block(blockerID, blockedID uint64) error
+	Unblock(blockerID, blockedID uint64) error
+	GetBlocked(userID uint64, page, limit int) ([]User, error)
+	Mute(muterID, mutedID uint64) error
+	Unmute(muterID, mutedID uint64) error
+	GetMuted(userID uint64, page, limit int) ([]User, error)
+}
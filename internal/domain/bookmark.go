@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+)
+
+// BookmarkCollection is a named group of a user's bookmarks
+type BookmarkCollection struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	UserID    uint64    `json:"user_id" gorm:"not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	Name      string    `json:"name" gorm:"not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Bookmark is a post saved by a user, optionally filed in a collection
+type Bookmark struct {
+	ID           uint64    `json:"id" gorm:"primaryKey"`
+	UserID       uint64    `json:"user_id" gorm:"not null;uniqueIndex:idx_bookmarks_user_post"`
+	PostID       uint64    `json:"post_id" gorm:"not null;uniqueIndex:idx_bookmarks_user_post;index"`
+	CollectionID *uint64   `json:"collection_id,omitempty" gorm:"index"`
+	Post         *Post     `json:"post,omitempty" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BookmarkPage is a page of bookmarks. NextCursor is zero on the last page.
+type BookmarkPage struct {
+	Bookmarks  []Bookmark `json:"bookmarks"`
+	NextCursor uint64     `json:"next_cursor"`
+}
+
+type BookmarkRepository interface {
+	Upsert(bookmark *Bookmark) error
+	Delete(userID, postID uint64) error
+	List(userID uint64, collectionID *uint64, cursor uint64, limit int) ([]Bookmark, error)
+	GetBookmarkedPostIDs(userID uint64) ([]uint64, error)
+	CreateCollection(collection *BookmarkCollection) error
+	GetCollection(id uint64) (*BookmarkCollection, error)
+	ListCollections(userID uint64) ([]BookmarkCollection, error)
+	DeleteCollection(id uint64) error
+}
+
+type BookmarkUsecase interface {
+	Bookmark(userID, postID uint64, collectionID *uint64) error
+	RemoveBookmark(userID, postID uint64) error
+	GetBookmarks(userID uint64, collectionID *uint64, cursor uint64, limit int) (*BookmarkPage, error)
+	GetBookmarkedPostIDs(userID uint64) (map[uint64]bool, error)
+	CreateCollection(userID uint64, name string) (*BookmarkCollection, error)
+	GetCollections(userID uint64) ([]BookmarkCollection, error)
+	DeleteCollection(userID, collectionID uint64) error
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// CloseFriend lets FriendID see the posts UserID shares with close friends
+type CloseFriend struct {
+	UserID    uint64    `json:"user_id" gorm:"primaryKey"`
+	FriendID  uint64    `json:"friend_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CloseFriendRepository interface {
+	Add(closeFriend *CloseFriend) error
+	Remove(userID, friendID uint64) error
+	IsCloseFriend(userID, friendID uint64) (bool, error)
+	List(userID uint64, page, limit int) ([]User, error)
+}
+
+type CloseFriendUsecase interface {
+	AddCloseFriend(userID, friendID uint64) error
+	RemoveCloseFriend(userID, friendID uint64) error
+	GetCloseFriends(userID uint64, page, limit int) ([]User, error)
+}
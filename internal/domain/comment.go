@@ -2,15 +2,20 @@ package domain
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Comment struct {
-	ID        uint64    `json:"id" gorm:"primaryKey"`
-	PostID    uint64    `json:"post_id" gorm:"not null"`
-	UserID    uint64    `json:"user_id" gorm:"not null"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint64         `json:"id" gorm:"primaryKey"`
+	PostID    uint64         `json:"post_id" gorm:"not null"`
+	UserID    uint64         `json:"user_id" gorm:"not null"`
+	Content   string         `json:"content"`
+	EditCount int64          `json:"edit_count" gorm:"not null;default:0"`
+	HiddenAt  *time.Time     `json:"hidden_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type CommentRepository interface {
@@ -19,12 +24,19 @@ type CommentRepository interface {
 	GetByPostID(postID uint64, page, limit int) ([]Comment, error)
 	Update(comment *Comment) error
 	Delete(id uint64) error
+	SetHidden(id uint64, hiddenAt *time.Time) error
+	CountRecentByContent(userID uint64, content string, since time.Time) (int64, error)
+	GetRevisions(commentID uint64, page, limit int) ([]CommentRevision, error)
+	GetPurgeable(deletedBefore time.Time, limit int) ([]Comment, error)
+	Purge(id uint64) error
 }
 
 type CommentUsecase interface {
 	CreateComment(comment *Comment) error
 	GetComment(id uint64) (*Comment, error)
-	GetPostComments(postID uint64, page, limit int) ([]Comment, error)
+	GetPostComments(postID, viewerID uint64, page, limit int) ([]Comment, error)
 	UpdateComment(comment *Comment) error
 	DeleteComment(id uint64) error
+	SetCommentHidden(id uint64, hidden bool) error
+	GetCommentRevisions(postID, commentID, viewerID uint64, page, limit int) ([]CommentRevision, error)
 }
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+)
+
+// Content filter decisions
+const (
+	FilterDecisionAllow  = "allow"
+	FilterDecisionFlag   = "flag"
+	FilterDecisionReject = "reject"
+)
+
+// FilterDecision records a filter flagging or rejecting content. TargetID is
+// set once flagged content is written and stays nil for rejected content.
+type FilterDecision struct {
+	ID         uint64    `json:"id" gorm:"primaryKey"`
+	UserID     uint64    `json:"user_id" gorm:"not null;index"`
+	TargetType string    `json:"target_type" gorm:"not null"`
+	TargetID   *uint64   `json:"target_id,omitempty"`
+	Filter     string    `json:"filter" gorm:"not null"`
+	Decision   string    `json:"decision" gorm:"not null;index"`
+	Reason     string    `json:"reason"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ContentScreening is the outcome of running content through the filters
+type ContentScreening struct {
+	Decision    string
+	Reason      string
+	DecisionIDs []uint64
+}
+
+type FilterDecisionRepository interface {
+	Create(decisions []FilterDecision) error
+	AttachTarget(ids []uint64, targetID uint64) error
+	List(decision string, page, limit int) ([]FilterDecision, error)
+}
+
+type ContentFilterUsecase interface {
+	Screen(userID uint64, targetType, content string) (*ContentScreening, error)
+	Attach(screening *ContentScreening, targetID uint64)
+	ListDecisions(decision string, page, limit int) ([]FilterDecision, error)
+}
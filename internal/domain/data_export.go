@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// Data export statuses
+const (
+	DataExportPending   = "pending"
+	DataExportRunning   = "running"
+	DataExportCompleted = "completed"
+	DataExportFailed    = "failed"
+)
+
+// DataExport is a user's request for a copy of their data. A worker builds a
+// ZIP archive, which can be downloaded through a signed link until ExpiresAt.
+type DataExport struct {
+	ID          uint64     `json:"id" gorm:"primaryKey"`
+	UserID      uint64     `json:"user_id" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"not null;default:pending;index"`
+	IncludeHTML bool       `json:"include_html" gorm:"not null;default:false"`
+	FileURL     string     `json:"-"`
+	Size        int64      `json:"size" gorm:"not null;default:0"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+
+	// DownloadURL is the signed link of a completed export, it is not stored
+	DownloadURL string `json:"download_url,omitempty" gorm:"-"`
+}
+
+// IsActive reports whether an export is still waiting for or being built
+func (e *DataExport) IsActive() bool {
+	return e.Status == DataExportPending || e.Status == DataExportRunning
+}
+
+type DataExportRepository interface {
+	Create(export *DataExport) error
+	GetByID(id uint64) (*DataExport, error)
+	GetActiveByUserID(userID uint64) (*DataExport, error)
+	Update(export *DataExport) error
+	Delete(id uint64) error
+	Claim(staleBefore time.Time) (*DataExport, error)
+	GetExpired(now time.Time, limit int) ([]DataExport, error)
+	GetPosts(userID, afterID uint64, limit int) ([]Post, error)
+	GetComments(userID, afterID uint64, limit int) ([]Comment, error)
+	GetLikes(userID, afterID uint64, limit int) ([]Like, error)
+}
+
+type DataExportUsecase interface {
+	RequestExport(userID uint64, includeHTML bool) (*DataExport, error)
+	GetExport(id, userID uint64) (*DataExport, error)
+	OpenDownload(id uint64, expires int64, signature string) (*DataExport, io.ReadCloser, error)
+	ProcessNext(batchSize int) (bool, error)
+	DeleteExpired(limit int) (int, error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+)
+
+// Follow statuses returned when following a user
+const (
+	FollowStatusFollowing = "following"
+	FollowStatusRequested = "requested"
+)
+
+// FollowRequest is a pending follow of a private account
+type FollowRequest struct {
+	ID          uint64    `json:"id" gorm:"primaryKey"`
+	RequesterID uint64    `json:"requester_id" gorm:"not null;uniqueIndex:idx_follow_requests_pair"`
+	TargetID    uint64    `json:"target_id" gorm:"not null;uniqueIndex:idx_follow_requests_pair;index"`
+	Requester   *User     `json:"requester,omitempty" gorm:"foreignKey:RequesterID"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FollowRequestRepository interface {
+	Create(request *FollowRequest) error
+	GetByID(id uint64) (*FollowRequest, error)
+	GetPending(targetID uint64, page, limit int) ([]FollowRequest, error)
+	Approve(request *FollowRequest) error
+	ApproveAll(targetID uint64) ([]uint64, error)
+	Delete(requesterID, targetID uint64) error
+}
+
+type FollowRequestUsecase interface {
+	GetPending(userID uint64, page, limit int) ([]FollowRequest, error)
+	Approve(userID, requestID uint64) error
+	Reject(userID, requestID uint64) error
+}
@@ -0,0 +1,55 @@
+package domain
+
+// MaxDegreesOfSeparation is how many friendship hops are searched between two users
+const MaxDegreesOfSeparation = 3
+
+// RelationshipEdges are the direct links from a user to another user
+type RelationshipEdges struct {
+	Following             bool `json:"following"`
+	FollowedBy            bool `json:"followed_by"`
+	FollowRequested       bool `json:"follow_requested"`
+	FollowRequestReceived bool `json:"follow_request_received"`
+	Blocking              bool `json:"blocking"`
+	BlockedBy             bool `json:"blocked_by"`
+	Muting                bool `json:"muting"`
+	CloseFriend           bool `json:"close_friend"`
+}
+
+// Relationship is how a user relates to another user as seen by a viewer.
+// Requests, blocks, mutes and close friends are only shown to the two users,
+// nil fields are hidden from the viewer. Degree is nil when the users are
+// more than MaxDegreesOfSeparation friendships apart.
+type Relationship struct {
+	UserID             uint64 `json:"user_id"`
+	OtherUserID        uint64 `json:"other_user_id"`
+	Following          bool   `json:"following"`
+	FollowedBy         bool   `json:"followed_by"`
+	IsFriend           bool   `json:"is_friend"`
+	MutualFriendsCount int64  `json:"mutual_friends_count"`
+	MutualFriends      []User `json:"mutual_friends"`
+	Degree             *int   `json:"degree"`
+
+	FollowRequested       *bool `json:"follow_requested,omitempty"`
+	FollowRequestReceived *bool `json:"follow_request_received,omitempty"`
+	Blocking              *bool `json:"blocking,omitempty"`
+	BlockedBy             *bool `json:"blocked_by,omitempty"`
+	Muting                *bool `json:"muting,omitempty"`
+	CloseFriend           *bool `json:"close_friend,omitempty"`
+}
+
+// GraphRepository queries the follow graph. Two users are friends when they
+// follow each other, degrees of separation count the friendships on the
+// shortest path between two users.
+type GraphRepository interface {
+	GetEdges(userID, otherID uint64) (*RelationshipEdges, error)
+	IsFriend(userID, otherID uint64) (bool, error)
+	GetMutualFriends(userID, otherID uint64, page, limit int) ([]User, error)
+	CountMutualFriends(userID, otherID uint64) (int64, error)
+	GetDegreeOfSeparation(userID, otherID uint64, maxDegree int) (int, error)
+}
+
+type GraphUsecase interface {
+	GetRelationship(viewerID, userID, otherID uint64) (*Relationship, error)
+	GetMutualFriends(viewerID, userID, otherID uint64, page, limit int) ([]User, error)
+	IsFriend(userID, otherID uint64) (bool, error)
+}
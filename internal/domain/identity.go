@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an external login provider
+type UserIdentity struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	UserID    uint64    `json:"user_id" gorm:"not null;index"`
+	Provider  string    `json:"provider" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `json:"-" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OAuthState is kept between the authorization redirect and the callback.
+// BindingHash ties it to the browser that started the login.
+type OAuthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	BindingHash  string `json:"binding_hash"`
+}
+
+type UserIdentityRepository interface {
+	Create(identity *UserIdentity) error
+	GetByProviderSubject(provider, subject string) (*UserIdentity, error)
+	GetByUserID(userID uint64) ([]UserIdentity, error)
+}
+
+type OAuthUsecase interface {
+	AuthorizationURL(provider string) (authURL, binding string, err error)
+	HandleCallback(provider, state, code, binding string) (*LoginResult, error)
+	GetIdentities(userID uint64) ([]UserIdentity, error)
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// Import job statuses
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// Kinds of imported records, in the order they are imported. Each kind only
+// refers to kinds imported before it.
+const (
+	ImportKindUser    = "user"
+	ImportKindPost    = "post"
+	ImportKindComment = "comment"
+	ImportKindLike    = "like"
+	ImportKindFollow  = "follow"
+	ImportStageDone   = "done"
+)
+
+// ImportKinds lists the kinds of imported records in import order
+var ImportKinds = []string{
+	ImportKindUser,
+	ImportKindPost,
+	ImportKindComment,
+	ImportKindLike,
+	ImportKindFollow,
+}
+
+// ImportJob loads an NDJSON file of another community. The file is read once
+// per kind of record, and Stage and Line checkpoint the progress so that an
+// interrupted import resumes after the last committed batch.
+type ImportJob struct {
+	ID      uint64 `json:"id" gorm:"primaryKey"`
+	Source  string `json:"source" gorm:"not null;index"`
+	FileURL string `json:"-" gorm:"not null"`
+	DryRun  bool   `json:"dry_run" gorm:"not null;default:false"`
+	Status  string `json:"status" gorm:"not null;default:pending;index"`
+	Stage   string `json:"stage" gorm:"not null"`
+	Line    int64  `json:"line" gorm:"not null;default:0"`
+
+	UsersImported    int64 `json:"users_imported" gorm:"not null;default:0"`
+	PostsImported    int64 `json:"posts_imported" gorm:"not null;default:0"`
+	CommentsImported int64 `json:"comments_imported" gorm:"not null;default:0"`
+	LikesImported    int64 `json:"likes_imported" gorm:"not null;default:0"`
+	FollowsImported  int64 `json:"follows_imported" gorm:"not null;default:0"`
+	Skipped          int64 `json:"skipped" gorm:"not null;default:0"`
+
+	// Validation errors are counted, the first ones are kept one per line
+	ErrorCount int64  `json:"error_count" gorm:"not null;default:0"`
+	Errors     string `json:"errors,omitempty"`
+
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ErrorList returns the kept validation errors
+func (j *ImportJob) ErrorList() []string {
+	if j.Errors == "" {
+		return nil
+	}
+	return strings.Split(j.Errors, "\n")
+}
+
+// ImportMapping maps the ID of an imported record in its source to the ID
+// it was given here
+type ImportMapping struct {
+	Source   string `json:"source" gorm:"primaryKey"`
+	Kind     string `json:"kind" gorm:"primaryKey"`
+	SourceID string `json:"source_id" gorm:"primaryKey"`
+	TargetID uint64 `json:"target_id" gorm:"not null"`
+}
+
+// ImportID is a record ID of the source, given as a JSON string or number
+type ImportID string
+
+func (id *ImportID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = ImportID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = ImportID(n.String())
+	return nil
+}
+
+// ImportRecord is a line of an import file. Type selects the kind of record
+// and the fields it uses, IDs refer to records of the same file.
+type ImportRecord struct {
+	Type        string    `json:"type"`
+	ID          ImportID  `json:"id"`
+	UserID      ImportID  `json:"user_id"`
+	PostID      ImportID  `json:"post_id"`
+	FollowerID  ImportID  `json:"follower_id"`
+	FollowingID ImportID  `json:"following_id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	Content     string    `json:"content"`
+	ImageURL    string    `json:"image_url"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ImportRepository interface {
+	Create(job *ImportJob) error
+	GetByID(id uint64) (*ImportJob, error)
+	Update(job *ImportJob) error
+	Claim(staleBefore time.Time) (*ImportJob, error)
+	ClaimByID(id uint64, staleBefore time.Time) (*ImportJob, error)
+	GetMappings(source, kind string, sourceIDs []string) (map[string]uint64, error)
+	GetTakenUsernamesAndEmails(usernames, emails []string) (map[string]bool, error)
+	ImportUsers(job *ImportJob, users []User, sourceIDs []string) error
+	ImportPosts(job *ImportJob, posts []Post, sourceIDs []string) error
+	ImportComments(job *ImportJob, comments []Comment, sourceIDs []string) error
+	ImportLikes(job *ImportJob, likes []Like, sourceIDs []string) error
+	ImportFollows(job *ImportJob, follows []Follow) error
+}
+
+type ImportUsecase interface {
+	CreateImport(source string, dryRun bool, file io.Reader) (*ImportJob, error)
+	GetImport(id uint64) (*ImportJob, error)
+	ResumeImport(id uint64) (*ImportJob, error)
+	RunImport(id uint64) (*ImportJob, error)
+	ProcessNext() (bool, error)
+}
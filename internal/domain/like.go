@@ -21,6 +21,6 @@ type LikeRepository interface {
 type LikeUsecase interface {
 	LikePost(postID, userID uint64) error
 	UnlikePost(postID, userID uint64) error
-	GetPostLikes(postID uint64, page, limit int) ([]Like, error)
+	GetPostLikes(postID, viewerID uint64, page, limit int) ([]Like, error)
 	HasUserLiked(postID, userID uint64) (bool, error)
 }
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+)
+
+// Lockout scopes
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// LoginLockout is an audit record of a temporary login lockout
+type LoginLockout struct {
+	ID          uint64    `json:"id" gorm:"primaryKey"`
+	Scope       string    `json:"scope" gorm:"not null"`
+	Username    string    `json:"username" gorm:"index"`
+	UserID      *uint64   `json:"user_id,omitempty" gorm:"index"`
+	IPAddress   string    `json:"ip_address" gorm:"not null"`
+	Failures    int       `json:"failures" gorm:"not null"`
+	LockedUntil time.Time `json:"locked_until" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LoginLockoutRepository interface {
+	Create(lockout *LoginLockout) error
+	List(page, limit int) ([]LoginLockout, error)
+}
@@ -2,33 +2,107 @@ package domain
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Post visibilities, from widest to narrowest audience
+const (
+	PostVisibilityPublic       = "public"
+	PostVisibilityFollowers    = "followers"
+	PostVisibilityCloseFriends = "close_friends"
+	PostVisibilityOnlyMe       = "only_me"
+)
+
+// Post statuses. Drafts and scheduled posts are only visible to their author
+// until they are published.
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
 )
 
 type Post struct {
-	ID        uint64    `json:"id" gorm:"primaryKey"`
-	UserID    uint64    `json:"user_id" gorm:"not null"`
-	Content   string    `json:"content"`
-	ImageURL  string    `json:"image_url,omitempty"`
-	Likes     []Like    `json:"likes,omitempty" gorm:"foreignKey:PostID"`
-	Comments  []Comment `json:"comments,omitempty" gorm:"foreignKey:PostID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint64 `json:"id" gorm:"primaryKey"`
+	UserID     uint64 `json:"user_id" gorm:"not null"`
+	Content    string `json:"content"`
+	ImageURL   string `json:"image_url,omitempty"`
+	Visibility string `json:"visibility" gorm:"not null;default:public"`
+
+	// Scheduled posts are published by the scheduler once PublishAt has passed
+	Status    string     `json:"status" gorm:"not null;default:published;index"`
+	PublishAt *time.Time `json:"publish_at,omitempty" gorm:"index"`
+
+	// Reposts reference OriginalPostID without content, quote posts add content
+	OriginalPostID *uint64 `json:"original_post_id,omitempty" gorm:"index"`
+	OriginalPost   *Post   `json:"original_post,omitempty" gorm:"foreignKey:OriginalPostID;constraint:OnDelete:SET NULL"`
+	ShareCount     int64   `json:"share_count" gorm:"not null;default:0"`
+
+	// Every edit of the content keeps the replaced content as a revision
+	EditCount int64 `json:"edit_count" gorm:"not null;default:0"`
+
+	Likes     []Like         `json:"likes,omitempty" gorm:"foreignKey:PostID"`
+	Comments  []Comment      `json:"comments,omitempty" gorm:"foreignKey:PostID"`
+	HiddenAt  *time.Time     `json:"hidden_at,omitempty" gorm:"index"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// IsRepost reports whether a post shares another post without commentary
+func (p *Post) IsRepost() bool {
+	return p.OriginalPostID != nil && p.Content == ""
+}
+
+// IsPending reports whether a post is a draft or waiting to be published
+func (p *Post) IsPending() bool {
+	return p.Status == PostStatusDraft || p.Status == PostStatusScheduled
 }
 
 type PostRepository interface {
 	Create(post *Post) error
 	GetByID(id uint64) (*Post, error)
-	GetByUserID(userID uint64, page, limit int) ([]Post, error)
+	GetByUserID(userID uint64, visibilities []string, page, limit int) ([]Post, error)
 	Update(post *Post) error
 	Delete(id uint64) error
 	GetNewsFeed(userID uint64, page, limit int) ([]Post, error)
+	SetHidden(id uint64, hiddenAt *time.Time) error
+	CountRecentByContent(userID uint64, content string, since time.Time) (int64, error)
+	GetRepost(userID, originalPostID uint64) (*Post, error)
+	IsShareable(id uint64) (bool, error)
+	GetShares(originalPostID uint64) ([]Post, error)
+	GetSharesByAuthor(authorID uint64) ([]Post, error)
+	GetDrafts(userID uint64, page, limit int) ([]Post, error)
+	UpdateDraft(post *Post) (bool, error)
+	DeleteDraft(id uint64) (bool, error)
+	PublishDraft(id uint64, publishedAt time.Time) (*Post, error)
+	PublishDue(now time.Time, limit int) ([]Post, error)
+	GetRevisions(postID uint64, page, limit int) ([]PostRevision, error)
+	GetDeleted(id uint64) (*Post, error)
+	Restore(post *Post) error
+	GetPurgeable(deletedBefore time.Time, limit int) ([]Post, error)
+	GetMediaURLs(id uint64) ([]string, error)
+	Purge(id uint64) error
 }
 
 type PostUsecase interface {
 	CreatePost(post *Post) error
-	GetPost(id uint64) (*Post, error)
-	GetUserPosts(userID uint64, page, limit int) ([]Post, error)
+	GetPost(id, viewerID uint64) (*Post, error)
+	GetUserPosts(userID, viewerID uint64, page, limit int) ([]Post, error)
 	UpdatePost(post *Post) error
 	DeletePost(id uint64) error
+	RemovePost(id uint64) error
+	RestorePost(id, userID uint64) (*Post, error)
 	GetNewsFeed(userID uint64, page, limit int) ([]Post, error)
+	SetPostHidden(id uint64, hidden bool) error
+	SharePost(userID, originalPostID uint64, content, visibility string) (*Post, error)
+	UndoRepost(userID, originalPostID uint64) error
+	CreateDraft(post *Post) error
+	GetDrafts(userID uint64, page, limit int) ([]Post, error)
+	GetDraft(id, userID uint64) (*Post, error)
+	UpdateDraft(post *Post) error
+	DeleteDraft(id, userID uint64) error
+	PublishDraft(id, userID uint64) (*Post, error)
+	PublishDuePosts(limit int) (int, error)
+	GetPostRevisions(postID, viewerID uint64, page, limit int) ([]PostRevision, error)
 }
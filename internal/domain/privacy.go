@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"time"
+)
+
+// Audiences a profile field can be shared with
+const (
+	FieldAudiencePublic    = "public"
+	FieldAudienceFollowers = "followers"
+	FieldAudienceOnlyMe    = "only_me"
+)
+
+// PrivacySettings is who can see each optional field of a user's profile.
+// Names, avatars and bios are always public. Users who never changed their
+// settings get DefaultPrivacySettings.
+type PrivacySettings struct {
+	UserID    uint64    `json:"user_id" gorm:"primaryKey"`
+	Email     string    `json:"email" gorm:"not null"`
+	Birthday  string    `json:"birthday" gorm:"not null"`
+	Location  string    `json:"location" gorm:"not null"`
+	Website   string    `json:"website" gorm:"not null"`
+	Pronouns  string    `json:"pronouns" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultPrivacySettings keeps the email to the user, shares the birthday
+// with followers and the rest of the profile with everyone
+func DefaultPrivacySettings(userID uint64) *PrivacySettings {
+	return &PrivacySettings{
+		UserID:   userID,
+		Email:    FieldAudienceOnlyMe,
+		Birthday: FieldAudienceFollowers,
+		Location: FieldAudiencePublic,
+		Website:  FieldAudiencePublic,
+		Pronouns: FieldAudiencePublic,
+	}
+}
+
+// PrivacyUpdate changes the audiences that are set and keeps the others
+type PrivacyUpdate struct {
+	Email    *string
+	Birthday *string
+	Location *string
+	Website  *string
+	Pronouns *string
+}
+
+// ProfileAccess is which optional profile fields a viewer can see
+type ProfileAccess struct {
+	Email    bool
+	Birthday bool
+	Location bool
+	Website  bool
+	Pronouns bool
+}
+
+// FullProfileAccess is the access of a user to their own profile
+var FullProfileAccess = ProfileAccess{Email: true, Birthday: true, Location: true, Website: true, Pronouns: true}
+
+// AccessFor returns the fields a viewer who is not the user can see,
+// depending on whether they follow the user
+func (s *PrivacySettings) AccessFor(isFollower bool) ProfileAccess {
+	sees := func(audience string) bool {
+		return audience == FieldAudiencePublic || (audience == FieldAudienceFollowers && isFollower)
+	}
+	return ProfileAccess{
+		Email:    sees(s.Email),
+		Birthday: sees(s.Birthday),
+		Location: sees(s.Location),
+		Website:  sees(s.Website),
+		Pronouns: sees(s.Pronouns),
+	}
+}
+
+type PrivacyRepository interface {
+	GetByUserID(userID uint64) (*PrivacySettings, error)
+	Save(settings *PrivacySettings) error
+}
+
+type PrivacyUsecase interface {
+	GetSettings(userID uint64) (*PrivacySettings, error)
+	UpdateSettings(userID uint64, update *PrivacyUpdate) (*PrivacySettings, error)
+	GetProfileAccess(viewerID, userID uint64) (*ProfileAccess, error)
+}
@@ -0,0 +1,7 @@
+package domain
+
+// PurgeUsecase permanently removes soft deleted users, posts and comments once
+// they can no longer be restored
+type PurgeUsecase interface {
+	PurgeExpired(limit int) (int, error)
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAlreadyReported is returned when a user reports the same target twice
+var ErrAlreadyReported = errors.New("already reported")
+
+// Report target types
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+)
+
+// Report reasons
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHateSpeech     = "hate_speech"
+	ReportReasonViolence       = "violence"
+	ReportReasonNudity         = "nudity"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonOther          = "other"
+)
+
+// Report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Moderation actions taken on a reported target
+const (
+	ModerationActionHide    = "hide"
+	ModerationActionRestore = "restore"
+	ModerationActionRemove  = "remove"
+	ModerationActionWarn    = "warn"
+	ModerationActionSuspend = "suspend"
+	ModerationActionDismiss = "dismiss"
+)
+
+// Report is a user's complaint about a post or comment. A user can report a
+// target only once.
+type Report struct {
+	ID         uint64     `json:"id" gorm:"primaryKey"`
+	ReporterID uint64     `json:"reporter_id" gorm:"not null;uniqueIndex:idx_reports_reporter_target"`
+	TargetType string     `json:"target_type" gorm:"not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"`
+	TargetID   uint64     `json:"target_id" gorm:"not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"`
+	Reason     string     `json:"reason" gorm:"not null"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status" gorm:"not null;default:open;index"`
+	ResolvedBy *uint64    `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ModerationQueueItem is a target with open reports, aggregated for the queue
+type ModerationQueueItem struct {
+	TargetType      string    `json:"target_type"`
+	TargetID        uint64    `json:"target_id"`
+	ReportCount     int64     `json:"report_count"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
+}
+
+// ModerationAction records an action on a target. ModeratorID is nil for
+// actions taken automatically, such as hiding after too many reports.
+type ModerationAction struct {
+	ID           uint64    `json:"id" gorm:"primaryKey"`
+	ModeratorID  *uint64   `json:"moderator_id,omitempty"`
+	TargetType   string    `json:"target_type" gorm:"not null"`
+	TargetID     uint64    `json:"target_id" gorm:"not null"`
+	TargetUserID uint64    `json:"target_user_id" gorm:"not null;index"`
+	Action       string    `json:"action" gorm:"not null"`
+	Note         string    `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ReportRepository interface {
+	// Create returns ErrAlreadyReported if the reporter already reported the target
+	Create(report *Report) error
+	Exists(reporterID uint64, targetType string, targetID uint64) (bool, error)
+	CountOpen(targetType string, targetID uint64) (int64, error)
+	GetQueue(page, limit int) ([]ModerationQueueItem, error)
+	GetByTarget(targetType string, targetID uint64) ([]Report, error)
+	ResolveTarget(targetType string, targetID uint64, status string, moderatorID uint64) error
+	CreateAction(action *ModerationAction) error
+	GetActionsByUser(userID uint64, action string, page, limit int) ([]ModerationAction, error)
+}
+
+type ModerationUsecase interface {
+	ReportPost(reporterID, postID uint64, reason, details string) (*Report, error)
+	ReportComment(reporterID, postID, commentID uint64, reason, details string) (*Report, error)
+	GetQueue(page, limit int) ([]ModerationQueueItem, error)
+	GetTargetReports(targetType string, targetID uint64) ([]Report, error)
+	TakeAction(moderatorID uint64, targetType string, targetID uint64, action, note string) (*ModerationAction, error)
+	GetWarnings(userID uint64, page, limit int) ([]ModerationAction, error)
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+)
+
+// PostRevision is the content a post had before one of its edits. EditedAt
+// is when the edit replaced it.
+type PostRevision struct {
+	ID       uint64    `json:"id" gorm:"primaryKey"`
+	PostID   uint64    `json:"post_id" gorm:"not null;index"`
+	Content  string    `json:"content"`
+	ImageURL string    `json:"image_url,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// CommentRevision is the content a comment had before one of its edits
+type CommentRevision struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	CommentID uint64    `json:"comment_id" gorm:"not null;index"`
+	Content   string    `json:"content"`
+	EditedAt  time.Time `json:"edited_at"`
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "errors"
+
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions granted by roles
+const (
+	PermissionListUsers      = "users:list"
+	PermissionSuspendUsers   = "users:suspend"
+	PermissionManageRoles    = "users:roles"
+	PermissionDeletePosts    = "posts:delete"
+	PermissionDeleteComments = "comments:delete"
+	PermissionModerate       = "content:moderate"
+	PermissionDeleteAccounts = "users:delete"
+	PermissionImportData     = "data:import"
+)
+
+// Errors of administrative actions that the HTTP layer maps to status codes
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrPostNotFound    = errors.New("post not found")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrInvalidRole     = errors.New("invalid role")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// rolePermissions lists the permissions of each role
+var rolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionListUsers,
+		PermissionSuspendUsers,
+		PermissionDeletePosts,
+		PermissionDeleteComments,
+		PermissionModerate,
+	},
+	RoleAdmin: {
+		PermissionListUsers,
+		PermissionSuspendUsers,
+		PermissionManageRoles,
+		PermissionDeletePosts,
+		PermissionDeleteComments,
+		PermissionModerate,
+		PermissionDeleteAccounts,
+		PermissionImportData,
+	},
+}
+
+// roleRanks orders roles so that users can only act on less privileged users
+var roleRanks = map[string]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasPermission reports whether role grants permission. Unknown roles have no permissions.
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Outranks reports whether role is more privileged than other
+func Outranks(role, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
+
+// IsSuspended reports whether the user is currently suspended
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+type AdminUsecase interface {
+	ListUsers(page, limit int) ([]User, error)
+	SuspendUser(actorID, userID uint64, reason string) (*User, error)
+	UnsuspendUser(actorID, userID uint64) (*User, error)
+	SetRole(actorID, userID uint64, role string) (*User, error)
+	DeletePost(postID uint64) error
+	DeleteComment(commentID uint64) error
+}
@@ -0,0 +1,38 @@
+package domain
+
+// Why an account is suggested
+const (
+	SuggestionReasonMutual  = "mutual"
+	SuggestionReasonPopular = "popular"
+)
+
+// Suggestion is an account a user may know. Friends of friends are ranked by
+// how many of the accounts the user follows follow them, popular accounts
+// fill the list of users who follow few or no accounts.
+type Suggestion struct {
+	User          User   `json:"user"`
+	Reason        string `json:"reason"`
+	MutualCount   int64  `json:"mutual_count"`
+	MutualPreview []User `json:"mutual_preview,omitempty"`
+}
+
+// SuggestionCandidate is a suggested account with its number of mutual connections
+type SuggestionCandidate struct {
+	UserID      uint64 `json:"user_id"`
+	MutualCount int64  `json:"mutual_count"`
+}
+
+type SuggestionRepository interface {
+	GetFriendsOfFriends(userID uint64, limit int) ([]SuggestionCandidate, error)
+	GetPopular(userID uint64, excludeIDs []uint64, limit int) ([]uint64, error)
+	GetMutualPreviews(userID uint64, candidateIDs []uint64, perCandidate int) (map[uint64][]User, error)
+	GetUsers(ids []uint64) ([]User, error)
+	FilterSuggestable(userID uint64, candidateIDs []uint64) ([]uint64, error)
+	GetUserIDs(afterID uint64, limit int) ([]uint64, error)
+}
+
+type SuggestionUsecase interface {
+	GetSuggestions(userID uint64, limit int) ([]Suggestion, error)
+	Refresh(userID uint64) ([]Suggestion, error)
+	RefreshBatch(afterID uint64, batchSize int) (uint64, int, error)
+}
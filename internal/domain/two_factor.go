@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+)
+
+// RecoveryCode is a single-use code that can replace a TOTP code. Only the
+// SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint64     `json:"id" gorm:"primaryKey"`
+	UserID    uint64     `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TwoFactorEnrollment is returned once when a user starts enrolling in 2FA
+type TwoFactorEnrollment struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginResult holds either an access token or, when a second factor is still
+// needed, a short-lived challenge token
+type LoginResult struct {
+	AccessToken       string `json:"token,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+}
+
+type TwoFactorRepository interface {
+	ReplaceRecoveryCodes(userID uint64, codeHashes []string) error
+	UseRecoveryCode(userID uint64, codeHash string) (bool, error)
+	DeleteRecoveryCodes(userID uint64) error
+	AdvanceCounter(userID uint64, counter int64) (bool, error)
+}
+
+type TwoFactorUsecase interface {
+	Enroll(userID uint64) (*TwoFactorEnrollment, error)
+	Confirm(userID uint64, code string) error
+	Disable(userID uint64, password string) error
+	VerifyCode(userID uint64, code string) (bool, error)
+}
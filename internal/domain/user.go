@@ -1,19 +1,104 @@
 package domain
 
 import (
+	"io"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint64    `json:"id" gorm:"primaryKey"`
-	Username  string    `json:"username" gorm:"unique;not null"`
-	Password  string    `json:"-" gorm:"not null"`
-	Email     string    `json:"email" gorm:"unique;not null"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Birthday  time.Time `json:"birthday"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint64     `json:"id" gorm:"primaryKey"`
+	Username        string     `json:"username" gorm:"unique;not null"`
+	Password        string     `json:"-" gorm:"not null"`
+	Email           string     `json:"email" gorm:"unique;not null"`
+	EmailVerified   bool       `json:"email_verified" gorm:"not null;default:false"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Birthday        time.Time  `json:"birthday"`
+	IsPrivate       bool       `json:"is_private" gorm:"not null;default:false"`
+
+	// Usernames can be changed again once the change cooldown has passed
+	UsernameChangedAt *time.Time `json:"username_changed_at,omitempty"`
+
+	// Profile images are stored media files replaced through uploads
+	AvatarURL string `json:"avatar_url,omitempty"`
+	CoverURL  string `json:"cover_url,omitempty"`
+	Bio       string `json:"bio"`
+	Website   string `json:"website"`
+	Location  string `json:"location"`
+	Pronouns  string `json:"pronouns"`
+
+	Role             string     `json:"role" gorm:"not null;default:user"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+
+	TwoFactorEnabled     bool   `json:"two_factor_enabled" gorm:"not null;default:false"`
+	TwoFactorSecret      string `json:"-"`
+	TwoFactorLastCounter int64  `json:"-" gorm:"not null;default:0"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Follow is a row of the followers table
+type Follow struct {
+	FollowerID  uint64    `json:"follower_id"`
+	FollowingID uint64    `json:"following_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UsernameChange records a former username of a user. The name stays
+// reserved for the user until ReservedUntil, and looking it up until then
+// leads to the user.
+type UsernameChange struct {
+	ID            uint64    `json:"id" gorm:"primaryKey"`
+	UserID        uint64    `json:"user_id" gorm:"not null;index"`
+	Username      string    `json:"username" gorm:"not null;index"`
+	ReservedUntil time.Time `json:"reserved_until" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Profile images of a user
+const (
+	ProfileImageAvatar = "avatar"
+	ProfileImageCover  = "cover"
+)
+
+// ProfileUpdate changes the profile fields that are set, nil fields keep
+// their value
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	Birthday  *time.Time
+	Bio       *string
+	Website   *string
+	Location  *string
+	Pronouns  *string
+}
+
+// ProfileStats are the counts shown on a profile
+type ProfileStats struct {
+	Posts     int64 `json:"posts"`
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+}
+
+// FollowCounts are the number of followers and followed accounts of a user
+type FollowCounts struct {
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+}
+
+// FollowListEntry is a user of a followers or following list, with when the
+// follow was made and how the viewer relates to the user
+type FollowListEntry struct {
+	User
+	FollowedAt   time.Time `json:"followed_at"`
+	FollowedByMe bool      `json:"followed_by_me"`
+	FollowsMe    bool      `json:"follows_me"`
 }
 
 type UserRepository interface {
@@ -21,22 +106,41 @@ type UserRepository interface {
 	GetByID(id uint64) (*User, error)
 	GetByUsername(username string) (*User, error)
 	GetByEmail(email string) (*User, error)
-	Update(user *User) error
+	GetByFormerUsername(username string, now time.Time) (*User, error)
+	IsUsernameTaken(username string, userID uint64, now time.Time) (bool, error)
+	ChangeUsername(user *User, change *UsernameChange) error
+	Update(user *User, columns ...string) error
 	Delete(id uint64) error
+	List(page, limit int) ([]User, error)
 	GetFollowers(userID uint64) ([]User, error)
 	GetFollowing(userID uint64) ([]User, error)
+	GetFollowersPage(userID, viewerID uint64, page, limit int) ([]FollowListEntry, error)
+	GetFollowingPage(userID, viewerID uint64, page, limit int) ([]FollowListEntry, error)
+	CountFollows(userID uint64) (*FollowCounts, error)
+	CountPosts(userID uint64) (int64, error)
+	GetMediaURLs(id uint64) ([]string, error)
 	Follow(followerID, followingID uint64) error
 	Unfollow(followerID, followingID uint64) error
+	IsFollowing(followerID, followingID uint64) (bool, error)
+	GetPurgeable(deletedBefore time.Time, limit int) ([]User, error)
+	Purge(id uint64) error
 }
 
 type UserUsecase interface {
 	Register(user *User) error
-	Login(username, password string) (string, error)
+	Login(username, password, code, ipAddress string) (*LoginResult, error)
+	CompleteTwoFactorLogin(challengeToken, code, ipAddress string) (*LoginResult, error)
 	GetProfile(id uint64) (*User, error)
-	UpdateProfile(user *User) error
-	DeleteProfile(id uint64) error
-	Follow(followerID, followingID uint64) error
+	GetByUsername(username string) (*User, error)
+	ChangeUsername(id uint64, username string) (*User, error)
+	UpdateProfile(id uint64, update *ProfileUpdate) (*User, error)
+	ChangePassword(id uint64, currentPassword, newPassword, code string) error
+	SetProfileImage(id uint64, kind string, image io.Reader) (*User, error)
+	DeleteProfileImage(id uint64, kind string) (*User, error)
+	SetPrivate(id uint64, private bool) error
+	Follow(followerID, followingID uint64) (string, error)
 	Unfollow(followerID, followingID uint64) error
-	GetFollowers(userID uint64) ([]User, error)
-	GetFollowing(userID uint64) ([]User, error)
+	GetFollowers(userID, viewerID uint64, page, limit int) ([]FollowListEntry, error)
+	GetFollowing(userID, viewerID uint64, page, limit int) ([]FollowListEntry, error)
+	GetProfileStats(userID uint64) (*ProfileStats, error)
 }
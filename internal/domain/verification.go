@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+)
+
+// Verification token purposes
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailChange       = "email_change"
+)
+
+// VerificationToken is a single-use token sent to a user by email. Only the
+// SHA-256 hash of the token is stored. Email change tokens carry the new
+// address, which replaces the current one once the token is used.
+type VerificationToken struct {
+	ID        uint64     `json:"id" gorm:"primaryKey"`
+	UserID    uint64     `json:"user_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	NewEmail  string     `json:"new_email,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type VerificationTokenRepository interface {
+	Create(token *VerificationToken) error
+	GetByHash(purpose, tokenHash string) (*VerificationToken, error)
+	MarkUsed(id uint64) (bool, error)
+	InvalidateForUser(userID uint64, purpose string) error
+}
+
+type VerificationUsecase interface {
+	SendEmailVerification(userID uint64) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	RequestEmailChange(userID uint64, newEmail, password, code string) error
+	ConfirmEmailChange(token string) error
+}
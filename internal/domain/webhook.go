@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	EventPostCreated     = "post.created"
+	EventPostUpdated     = "post.updated"
+	EventPostDeleted     = "post.deleted"
+	EventCommentCreated  = "comment.created"
+	EventCommentUpdated  = "comment.updated"
+	EventCommentDeleted  = "comment.deleted"
+	EventLikeCreated     = "like.created"
+	EventLikeDeleted     = "like.deleted"
+	EventFollowCreated   = "follow.created"
+	EventFollowDeleted   = "follow.deleted"
+	EventFollowRequested = "follow.requested"
+)
+
+// WebhookEvents lists every event type a webhook can subscribe to
+var WebhookEvents = []string{
+	EventPostCreated,
+	EventPostUpdated,
+	EventPostDeleted,
+	EventCommentCreated,
+	EventCommentUpdated,
+	EventCommentDeleted,
+	EventLikeCreated,
+	EventLikeDeleted,
+	EventFollowCreated,
+	EventFollowDeleted,
+	EventFollowRequested,
+}
+
+// IsValidWebhookEvent reports whether event is a known webhook event type
+func IsValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	ID                  uint64     `json:"id" gorm:"primaryKey"`
+	UserID              uint64     `json:"user_id" gorm:"not null;index"`
+	URL                 string     `json:"url" gorm:"not null"`
+	Events              string     `json:"events" gorm:"not null"`
+	Secret              string     `json:"-" gorm:"not null"`
+	Active              bool       `json:"active" gorm:"not null;default:true"`
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// EventList returns the subscribed event types
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// SetEvents stores the subscribed event types
+func (w *Webhook) SetEvents(events []string) {
+	w.Events = strings.Join(events, ",")
+}
+
+// Subscribes reports whether the webhook wants deliveries for event
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID           uint64     `json:"id" gorm:"primaryKey"`
+	WebhookID    uint64     `json:"webhook_id" gorm:"not null;index"`
+	Event        string     `json:"event" gorm:"not null"`
+	Payload      string     `json:"payload" gorm:"type:text;not null"`
+	Attempts     int        `json:"attempts" gorm:"not null;default:0"`
+	StatusCode   int        `json:"status_code"`
+	ResponseBody string     `json:"response_body" gorm:"type:text"`
+	Error        string     `json:"error"`
+	Success      bool       `json:"success" gorm:"not null;default:false"`
+	RedeliveryOf *uint64    `json:"redelivery_of,omitempty"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type WebhookRepository interface {
+	Create(webhook *Webhook) error
+	GetByID(id uint64) (*Webhook, error)
+	GetByUserID(userID uint64) ([]Webhook, error)
+	GetActiveByUserIDs(userIDs []uint64) ([]Webhook, error)
+	Update(webhook *Webhook) error
+	Delete(id uint64) error
+	RecordFailure(id uint64, maxFailures int) (bool, error)
+	ResetFailures(id uint64) error
+	CreateDelivery(delivery *WebhookDelivery) error
+	GetDeliveryByID(id uint64) (*WebhookDelivery, error)
+	GetDeliveries(webhookID uint64, page, limit int) ([]WebhookDelivery, error)
+	UpdateDelivery(delivery *WebhookDelivery) error
+}
+
+// WebhookDispatcher delivers events to the webhooks subscribed by the given users
+type WebhookDispatcher interface {
+	Dispatch(event string, data interface{}, userIDs ...uint64)
+}
+
+type WebhookUsecase interface {
+	WebhookDispatcher
+	CreateWebhook(webhook *Webhook) error
+	GetWebhook(id, userID uint64) (*Webhook, error)
+	GetUserWebhooks(userID uint64) ([]Webhook, error)
+	UpdateWebhook(webhook *Webhook) error
+	DeleteWebhook(id, userID uint64) error
+	GetDeliveries(webhookID, userID uint64, page, limit int) ([]WebhookDelivery, error)
+	Redeliver(webhookID, deliveryID, userID uint64) (*WebhookDelivery, error)
+}
@@ -18,20 +18,24 @@ type UserCache interface {
 	GetUser(ctx context.Context, id uint64) (*domain.User, error)
 	SetUser(ctx context.Context, user *domain.User) error
 	DeleteUser(ctx context.Context, id uint64) error
-	GetFollowers(ctx context.Context, userID uint64) ([]domain.User, error)
-	SetFollowers(ctx context.Context, userID uint64, followers []domain.User) error
-	GetFollowing(ctx context.Context, userID uint64) ([]domain.User, error)
-	SetFollowing(ctx context.Context, userID uint64, following []domain.User) error
+	GetFollowCounts(ctx context.Context, userID uint64) (*domain.FollowCounts, error)
+	SetFollowCounts(ctx context.Context, userID uint64, counts *domain.FollowCounts) error
+	DeleteFollows(ctx context.Context, userID uint64) error
 }
 
 type PostCache interface {
 	GetPost(ctx context.Context, id uint64) (*domain.Post, error)
 	SetPost(ctx context.Context, post *domain.Post) error
 	DeletePost(ctx context.Context, id uint64) error
-	GetUserPosts(ctx context.Context, userID uint64, page int) ([]domain.Post, error)
-	SetUserPosts(ctx context.Context, userID uint64, page int, posts []domain.Post) error
+	// User post pages are cached per audience so a page is only served to
+	// viewers allowed to see every post on it
+	GetUserPosts(ctx context.Context, userID uint64, audience string, page int) ([]domain.Post, error)
+	SetUserPosts(ctx context.Context, userID uint64, audience string, page int, posts []domain.Post) error
+	DeleteUserPosts(ctx context.Context, userID uint64) error
 	GetNewsFeed(ctx context.Context, userID uint64, page int) ([]domain.Post, error)
 	SetNewsFeed(ctx context.Context, userID uint64, page int, posts []domain.Post) error
+	// DeleteNewsFeeds drops every cached newsfeed page of the given users
+	DeleteNewsFeeds(ctx context.Context, userIDs ...uint64) error
 }
 
 type CommentCache interface {
@@ -47,3 +51,32 @@ type LikeCache interface {
 	GetLikeExists(ctx context.Context, postID, userID uint64) (bool, error)
 	SetLikeExists(ctx context.Context, postID, userID uint64, exists bool) error
 }
+
+// BookmarkCache keeps each user's bookmarked post IDs and bookmark pages
+type BookmarkCache interface {
+	GetBookmarkedPostIDs(ctx context.Context, userID uint64) ([]uint64, error)
+	SetBookmarkedPostIDs(ctx context.Context, userID uint64, postIDs []uint64) error
+	GetBookmarks(ctx context.Context, userID uint64, collectionID *uint64, cursor uint64, limit int) (*domain.BookmarkPage, error)
+	SetBookmarks(ctx context.Context, userID uint64, collectionID *uint64, cursor uint64, limit int, page *domain.BookmarkPage) error
+	DeleteBookmarks(ctx context.Context, userID uint64) error
+}
+
+// SuggestionCache keeps the follow suggestions precomputed for each user
+type SuggestionCache interface {
+	GetSuggestions(ctx context.Context, userID uint64) ([]domain.Suggestion, error)
+	SetSuggestions(ctx context.Context, userID uint64, suggestions []domain.Suggestion, ttl time.Duration) error
+}
+
+// LoginAttemptCache tracks failed logins per subject, e.g. "user:alice" or "ip:10.0.0.1"
+type LoginAttemptCache interface {
+	IncrementFailures(ctx context.Context, subject string, window time.Duration) (int64, error)
+	ResetFailures(ctx context.Context, subject string) error
+	Block(ctx context.Context, subject string, duration time.Duration) error
+	GetBlockedFor(ctx context.Context, subject string) (time.Duration, error)
+}
+
+// OAuthStateCache keeps pending social logins between the redirect and the callback
+type OAuthStateCache interface {
+	SetState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error
+	PopState(ctx context.Context, state string) (*domain.OAuthState, error)
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	redisClient "github.com/Dang-Hai-Tran/newfeed-go/pkg/cache"
+)
+
+type bookmarkCache struct {
+	redis *redisClient.RedisClient
+}
+
+// NewBookmarkCache creates a new Redis bookmark cache
+func NewBookmarkCache(redis *redisClient.RedisClient) cache.BookmarkCache {
+	return &bookmarkCache{redis: redis}
+}
+
+func (c *bookmarkCache) GetBookmarkedPostIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	key := fmt.Sprintf("user:%d:bookmarks:ids", userID)
+	data, err := c.redis.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var postIDs []uint64
+	if err := json.Unmarshal([]byte(data), &postIDs); err != nil {
+		return nil, err
+	}
+
+	return postIDs, nil
+}
+
+func (c *bookmarkCache) SetBookmarkedPostIDs(ctx context.Context, userID uint64, postIDs []uint64) error {
+	key := fmt.Sprintf("user:%d:bookmarks:ids", userID)
+	data, err := json.Marshal(postIDs)
+	if err != nil {
+		return err
+	}
+
+	return c.redis.Set(ctx, key, data, cache.DefaultCacheDuration)
+}
+
+func (c *bookmarkCache) GetBookmarks(ctx context.Context, userID uint64, collectionID *uint64, cursor uint64, limit int) (*domain.BookmarkPage, error) {
+	data, err := c.redis.Get(ctx, bookmarkPageKey(userID, collectionID, cursor, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var page domain.BookmarkPage
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+func (c *bookmarkCache) SetBookmarks(ctx context.Context, userID uint64, collectionID *uint64, cursor uint64, limit int, page *domain.BookmarkPage) error {
+	data, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	// Pages embed posts, so keep them short lived to pick up post edits
+	return c.redis.Set(ctx, bookmarkPageKey(userID, collectionID, cursor, limit), data, cache.ShortCacheDuration)
+}
+
+func (c *bookmarkCache) DeleteBookmarks(ctx context.Context, userID uint64) error {
+	pattern := fmt.Sprintf("user:%d:bookmarks:*", userID)
+	return c.redis.DeletePattern(ctx, pattern)
+}
+
+func bookmarkPageKey(userID uint64, collectionID *uint64, cursor uint64, limit int) string {
+	collection := "all"
+	if collectionID != nil {
+		collection = fmt.Sprintf("%d", *collectionID)
+	}
+	return fmt.Sprintf("user:%d:bookmarks:%s:cursor:%d:limit:%d", userID, collection, cursor, limit)
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	redisClient "github.com/Dang-Hai-Tran/newfeed-go/pkg/cache"
+)
+
+type loginAttemptCache struct {
+	redis *redisClient.RedisClient
+}
+
+// NewLoginAttemptCache creates a new Redis login attempt cache
+func NewLoginAttemptCache(redis *redisClient.RedisClient) cache.LoginAttemptCache {
+	return &loginAttemptCache{redis: redis}
+}
+
+func (c *loginAttemptCache) IncrementFailures(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("login:failures:%s", subject)
+	return c.redis.Incr(ctx, key, window)
+}
+
+func (c *loginAttemptCache) ResetFailures(ctx context.Context, subject string) error {
+	key := fmt.Sprintf("login:failures:%s", subject)
+	return c.redis.Delete(ctx, key)
+}
+
+func (c *loginAttemptCache) Block(ctx context.Context, subject string, duration time.Duration) error {
+	key := fmt.Sprintf("login:blocked:%s", subject)
+	return c.redis.Set(ctx, key, "1", duration)
+}
+
+func (c *loginAttemptCache) GetBlockedFor(ctx context.Context, subject string) (time.Duration, error) {
+	key := fmt.Sprintf("login:blocked:%s", subject)
+	return c.redis.TTL(ctx, key)
+}
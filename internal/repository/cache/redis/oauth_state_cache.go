@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	redisClient "github.com/Dang-Hai-Tran/newfeed-go/pkg/cache"
+)
+
+type oauthStateCache struct {
+	redis *redisClient.RedisClient
+}
+
+// NewOAuthStateCache creates a new Redis OAuth state cache
+func NewOAuthStateCache(redis *redisClient.RedisClient) cache.OAuthStateCache {
+	return &oauthStateCache{redis: redis}
+}
+
+func (c *oauthStateCache) SetState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error {
+	key := fmt.Sprintf("oauth:state:%s", state)
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return c.redis.Set(ctx, key, value, ttl)
+}
+
+// PopState returns and removes the state so that it can only be used once
+func (c *oauthStateCache) PopState(ctx context.Context, state string) (*domain.OAuthState, error) {
+	key := fmt.Sprintf("oauth:state:%s", state)
+	value, err := c.redis.GetDel(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var data domain.OAuthState
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
@@ -55,8 +56,12 @@ func (c *postCache) DeletePost(ctx context.Context, id uint64) error {
 	return c.redis.DeletePattern(ctx, pattern)
 }
 
-func (c *postCache) GetUserPosts(ctx context.Context, userID uint64, page int) ([]domain.Post, error) {
-	key := fmt.Sprintf("user:%d:posts:page:%d", userID, page)
+func (c *postCache) GetUserPosts(ctx context.Context, userID uint64, audience string, page int) ([]domain.Post, error) {
+	version, err := c.redis.Version(ctx, userPostsVersionKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("user:%d:posts:v%d:%s:page:%d", userID, version, audience, page)
 	data, err := c.redis.Get(ctx, key)
 	if err != nil {
 		return nil, err
@@ -70,8 +75,12 @@ func (c *postCache) GetUserPosts(ctx context.Context, userID uint64, page int) (
 	return posts, nil
 }
 
-func (c *postCache) SetUserPosts(ctx context.Context, userID uint64, page int, posts []domain.Post) error {
-	key := fmt.Sprintf("user:%d:posts:page:%d", userID, page)
+func (c *postCache) SetUserPosts(ctx context.Context, userID uint64, audience string, page int, posts []domain.Post) error {
+	version, err := c.redis.Version(ctx, userPostsVersionKey(userID))
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("user:%d:posts:v%d:%s:page:%d", userID, version, audience, page)
 	data, err := json.Marshal(posts)
 	if err != nil {
 		return err
@@ -80,8 +89,18 @@ func (c *postCache) SetUserPosts(ctx context.Context, userID uint64, page int, p
 	return c.redis.Set(ctx, key, data, cache.DefaultCacheDuration)
 }
 
+// DeleteUserPosts moves the user's posts pages to a new version, the old
+// pages expire on their own
+func (c *postCache) DeleteUserPosts(ctx context.Context, userID uint64) error {
+	return c.redis.BumpVersions(ctx, []string{userPostsVersionKey(userID)}, versionDuration)
+}
+
 func (c *postCache) GetNewsFeed(ctx context.Context, userID uint64, page int) ([]domain.Post, error) {
-	key := fmt.Sprintf("user:%d:newsfeed:page:%d", userID, page)
+	version, err := c.redis.Version(ctx, newsFeedVersionKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("user:%d:newsfeed:v%d:page:%d", userID, version, page)
 	data, err := c.redis.Get(ctx, key)
 	if err != nil {
 		return nil, err
@@ -96,7 +115,11 @@ func (c *postCache) GetNewsFeed(ctx context.Context, userID uint64, page int) ([
 }
 
 func (c *postCache) SetNewsFeed(ctx context.Context, userID uint64, page int, posts []domain.Post) error {
-	key := fmt.Sprintf("user:%d:newsfeed:page:%d", userID, page)
+	version, err := c.redis.Version(ctx, newsFeedVersionKey(userID))
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("user:%d:newsfeed:v%d:page:%d", userID, version, page)
 	data, err := json.Marshal(posts)
 	if err != nil {
 		return err
@@ -105,3 +128,25 @@ func (c *postCache) SetNewsFeed(ctx context.Context, userID uint64, page int, po
 	// Cache newsfeed for a shorter duration since it's more dynamic
 	return c.redis.Set(ctx, key, data, cache.ShortCacheDuration)
 }
+
+// DeleteNewsFeeds moves the newsfeeds of the users to a new version in a
+// single round trip instead of scanning for their pages
+func (c *postCache) DeleteNewsFeeds(ctx context.Context, userIDs ...uint64) error {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = newsFeedVersionKey(userID)
+	}
+	return c.redis.BumpVersions(ctx, keys, versionDuration)
+}
+
+// versionDuration keeps page versions well beyond the lifetime of the pages,
+// so a version only resets once no page of it can be cached anymore
+const versionDuration = 24 * time.Hour
+
+func userPostsVersionKey(userID uint64) string {
+	return fmt.Sprintf("user:%d:posts:version", userID)
+}
+
+func newsFeedVersionKey(userID uint64) string {
+	return fmt.Sprintf("user:%d:newsfeed:version", userID)
+}
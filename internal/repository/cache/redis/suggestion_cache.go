@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	redisClient "github.com/Dang-Hai-Tran/newfeed-go/pkg/cache"
+)
+
+type suggestionCache struct {
+	redis *redisClient.RedisClient
+}
+
+// NewSuggestionCache creates a new Redis suggestion cache
+func NewSuggestionCache(redis *redisClient.RedisClient) cache.SuggestionCache {
+	return &suggestionCache{redis: redis}
+}
+
+func (c *suggestionCache) GetSuggestions(ctx context.Context, userID uint64) ([]domain.Suggestion, error) {
+	key := fmt.Sprintf("user:%d:suggestions", userID)
+	data, err := c.redis.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []domain.Suggestion
+	if err := json.Unmarshal([]byte(data), &suggestions); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (c *suggestionCache) SetSuggestions(ctx context.Context, userID uint64, suggestions []domain.Suggestion, ttl time.Duration) error {
+	key := fmt.Sprintf("user:%d:suggestions", userID)
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return err
+	}
+
+	return c.redis.Set(ctx, key, data, ttl)
+}
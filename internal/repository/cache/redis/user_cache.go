@@ -49,24 +49,24 @@ func (c *userCache) DeleteUser(ctx context.Context, id uint64) error {
 	return c.redis.Delete(ctx, key)
 }
 
-func (c *userCache) GetFollowers(ctx context.Context, userID uint64) ([]domain.User, error) {
-	key := fmt.Sprintf("user:%d:followers", userID)
+func (c *userCache) GetFollowCounts(ctx context.Context, userID uint64) (*domain.FollowCounts, error) {
+	key := fmt.Sprintf("user:%d:follow_counts", userID)
 	data, err := c.redis.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
-	var followers []domain.User
-	if err := json.Unmarshal([]byte(data), &followers); err != nil {
+	var counts domain.FollowCounts
+	if err := json.Unmarshal([]byte(data), &counts); err != nil {
 		return nil, err
 	}
 
-	return followers, nil
+	return &counts, nil
 }
 
-func (c *userCache) SetFollowers(ctx context.Context, userID uint64, followers []domain.User) error {
-	key := fmt.Sprintf("user:%d:followers", userID)
-	data, err := json.Marshal(followers)
+func (c *userCache) SetFollowCounts(ctx context.Context, userID uint64, counts *domain.FollowCounts) error {
+	key := fmt.Sprintf("user:%d:follow_counts", userID)
+	data, err := json.Marshal(counts)
 	if err != nil {
 		return err
 	}
@@ -74,27 +74,7 @@ func (c *userCache) SetFollowers(ctx context.Context, userID uint64, followers [
 	return c.redis.Set(ctx, key, data, cache.DefaultCacheDuration)
 }
 
-func (c *userCache) GetFollowing(ctx context.Context, userID uint64) ([]domain.User, error) {
-	key := fmt.Sprintf("user:%d:following", userID)
-	data, err := c.redis.Get(ctx, key)
-	if err != nil {
-		return nil, err
-	}
-
-	var following []domain.User
-	if err := json.Unmarshal([]byte(data), &following); err != nil {
-		return nil, err
-	}
-
-	return following, nil
-}
-
-func (c *userCache) SetFollowing(ctx context.Context, userID uint64, following []domain.User) error {
-	key := fmt.Sprintf("user:%d:following", userID)
-	data, err := json.Marshal(following)
-	if err != nil {
-		return err
-	}
-
-	return c.redis.Set(ctx, key, data, cache.DefaultCacheDuration)
+// DeleteFollows drops the cached follow counts of a user
+func (c *userCache) DeleteFollows(ctx context.Context, userID uint64) error {
+	return c.redis.Delete(ctx, fmt.Sprintf("user:%d:follow_counts", userID))
 }
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type accountDeletionRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountDeletionRepository creates a new instance of AccountDeletionRepository
+func NewAccountDeletionRepository(db *gorm.DB) domain.AccountDeletionRepository {
+	return &accountDeletionRepository{db: db}
+}
+
+// Create queues a deletion and soft deletes its user in one transaction, a
+// closed account always has a deletion removing its content
+func (r *accountDeletionRepository) Create(deletion *domain.AccountDeletion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(deletion).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.User{}, deletion.UserID).Error
+	})
+}
+
+func (r *accountDeletionRepository) GetByID(id uint64) (*domain.AccountDeletion, error) {
+	var deletion domain.AccountDeletion
+	err := r.db.First(&deletion, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (r *accountDeletionRepository) Update(deletion *domain.AccountDeletion) error {
+	return r.db.Save(deletion).Error
+}
+
+// Claim marks the oldest pending deletion as running and returns it. Running
+// deletions not updated since staleBefore are claimed again, their worker is
+// assumed to be gone. Returns nil when there is nothing to claim.
+func (r *accountDeletionRepository) Claim(staleBefore time.Time) (*domain.AccountDeletion, error) {
+	var deletions []domain.AccountDeletion
+	err := r.db.Raw(`
+		UPDATE account_deletions SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM account_deletions
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, domain.AccountDeletionRunning, time.Now(),
+		domain.AccountDeletionPending, domain.AccountDeletionRunning, staleBefore).Scan(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(deletions) == 0 {
+		return nil, nil
+	}
+	return &deletions[0], nil
+}
+
+// GetPosts returns up to limit posts of a user, deleted ones included
+func (r *accountDeletionRepository) GetPosts(userID uint64, limit int) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetComments returns up to limit comments of a user, deleted ones included
+func (r *accountDeletionRepository) GetComments(userID uint64, limit int) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Order("id").
+		Limit(limit).
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// DeleteLikes deletes up to limit likes of a user and returns them
+func (r *accountDeletionRepository) DeleteLikes(userID uint64, limit int) ([]domain.Like, error) {
+	var likes []domain.Like
+	err := r.db.Raw(`
+		DELETE FROM likes
+		WHERE id IN (SELECT id FROM likes WHERE user_id = ? LIMIT ?)
+		RETURNING *
+	`, userID, limit).Scan(&likes).Error
+	if err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+// DeleteFollows deletes up to limit follows from or to a user and returns them
+func (r *accountDeletionRepository) DeleteFollows(userID uint64, limit int) ([]domain.Follow, error) {
+	var follows []domain.Follow
+	err := r.db.Raw(`
+		DELETE FROM followers
+		WHERE (follower_id, following_id) IN (
+			SELECT follower_id, following_id FROM followers
+			WHERE follower_id = ? OR following_id = ?
+			LIMIT ?
+		)
+		RETURNING follower_id, following_id, created_at
+	`, userID, userID, limit).Scan(&follows).Error
+	if err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// DeleteAccountData deletes the remaining rows of a user, such as blocks,
+// bookmarks, webhooks, credentials, username reservations and privacy
+// settings, and returns how many rows were deleted. Moderation records made by the user are kept without their name.
+func (r *accountDeletionRepository) DeleteAccountData(userID uint64) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deletes := []struct {
+			model interface{}
+			query string
+			args  []interface{}
+		}{
+			{&domain.FollowRequest{}, "requester_id = ? OR target_id = ?", []interface{}{userID, userID}},
+			{&domain.Block{}, "blocker_id = ? OR blocked_id = ?", []interface{}{userID, userID}},
+			{&domain.Mute{}, "muter_id = ? OR muted_id = ?", []interface{}{userID, userID}},
+			{&domain.CloseFriend{}, "user_id = ? OR friend_id = ?", []interface{}{userID, userID}},
+			{&domain.Bookmark{}, "user_id = ?", []interface{}{userID}},
+			{&domain.BookmarkCollection{}, "user_id = ?", []interface{}{userID}},
+			{&domain.WebhookDelivery{}, "webhook_id IN (?)", []interface{}{tx.Model(&domain.Webhook{}).Select("id").Where("user_id = ?", userID)}},
+			{&domain.Webhook{}, "user_id = ?", []interface{}{userID}},
+			{&domain.UserIdentity{}, "user_id = ?", []interface{}{userID}},
+			{&domain.RecoveryCode{}, "user_id = ?", []interface{}{userID}},
+			{&domain.VerificationToken{}, "user_id = ?", []interface{}{userID}},
+			{&domain.UsernameChange{}, "user_id = ?", []interface{}{userID}},
+			{&domain.PrivacySettings{}, "user_id = ?", []interface{}{userID}},
+			{&domain.FilterDecision{}, "user_id = ?", []interface{}{userID}},
+			{&domain.LoginLockout{}, "user_id = ? OR username IN (?)", []interface{}{userID, tx.Unscoped().Model(&domain.User{}).Select("username").Where("id = ?", userID)}},
+			{&domain.Report{}, "reporter_id = ?", []interface{}{userID}},
+			{&domain.ModerationAction{}, "target_user_id = ?", []interface{}{userID}},
+		}
+		for _, d := range deletes {
+			result := tx.Where(d.query, d.args...).Delete(d.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+		}
+
+		// Finished exports expire now so that their archives are deleted,
+		// exports still to be built are dropped
+		err := tx.Where("user_id = ? AND status <> ?", userID, domain.DataExportCompleted).
+			Delete(&domain.DataExport{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&domain.DataExport{}).
+			Where("user_id = ?", userID).
+			UpdateColumn("expires_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		// Keep the moderation history of former moderators anonymously
+		err = tx.Model(&domain.Report{}).
+			Where("resolved_by = ?", userID).
+			UpdateColumn("resolved_by", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&domain.ModerationAction{}).
+			Where("moderator_id = ?", userID).
+			UpdateColumn("moderator_id", nil).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
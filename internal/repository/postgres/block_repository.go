@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type blockRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository creates a new instance of BlockRepository
+func NewBlockRepository(db *gorm.DB) domain.BlockRepository {
+	return &blockRepository{db: db}
+}
+
+// Block stores a block and removes the follows, follow requests and close
+// friends between both users
+func (r *blockRepository) Block(block *domain.Block) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO blocks (blocker_id, blocked_id, created_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT DO NOTHING
+		`, block.BlockerID, block.BlockedID, block.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`
+			DELETE FROM followers
+			WHERE (follower_id = ? AND following_id = ?)
+			OR (follower_id = ? AND following_id = ?)
+		`, block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`
+			DELETE FROM follow_requests
+			WHERE (requester_id = ? AND target_id = ?)
+			OR (requester_id = ? AND target_id = ?)
+		`, block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			DELETE FROM close_friends
+			WHERE (user_id = ? AND friend_id = ?)
+			OR (user_id = ? AND friend_id = ?)
+		`, block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID).Error
+	})
+}
+
+func (r *blockRepository) Unblock(blockerID, blockedID uint64) error {
+	return r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&domain.Block{}).Error
+}
+
+// IsBlocked reports whether either user has blocked the other
+func (r *blockRepository) IsBlocked(userID, otherID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetBlockedIDs returns the users that userID has blocked or is blocked by
+func (r *blockRepository) GetBlockedIDs(userID uint64) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Raw(`
+		SELECT blocked_id FROM blocks WHERE blocker_id = ?
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = ?
+	`, userID, userID).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *blockRepository) GetBlocked(userID uint64, page, limit int) ([]domain.User, error) {
+	var users []domain.User
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+` FROM users u
+		INNER JOIN blocks b ON b.blocked_id = u.id
+		WHERE b.blocker_id = ? AND u.deleted_at IS NULL
+		ORDER BY b.created_at DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *blockRepository) Mute(mute *domain.Mute) error {
+	return r.db.Exec(`
+		INSERT INTO mutes (muter_id, muted_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, mute.MuterID, mute.MutedID, mute.CreatedAt).Error
+}
+
+func (r *blockRepository) Unmute(muterID, mutedID uint64) error {
+	return r.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&domain.Mute{}).Error
+}
+
+func (r *blockRepository) GetMuted(userID uint64, page, limit int) ([]domain.User, error) {
+	var users []domain.User
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+` FROM users u
+		INNER JOIN mutes m ON m.muted_id = u.id
+		WHERE m.muter_id = ? AND u.deleted_at IS NULL
+		ORDER BY m.created_at DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+// NewBookmarkRepository creates a new instance of BookmarkRepository
+func NewBookmarkRepository(db *gorm.DB) domain.BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+// Upsert bookmarks a post, moving an existing bookmark to the given collection
+func (r *bookmarkRepository) Upsert(bookmark *domain.Bookmark) error {
+	return r.db.Exec(`
+		INSERT INTO bookmarks (user_id, post_id, collection_id, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, post_id) DO UPDATE SET collection_id = EXCLUDED.collection_id
+	`, bookmark.UserID, bookmark.PostID, bookmark.CollectionID, bookmark.CreatedAt).Error
+}
+
+func (r *bookmarkRepository) Delete(userID, postID uint64) error {
+	return r.db.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&domain.Bookmark{}).Error
+}
+
+// List returns bookmarks newest first, starting after the bookmark ID cursor
+func (r *bookmarkRepository) List(userID uint64, collectionID *uint64, cursor uint64, limit int) ([]domain.Bookmark, error) {
+	var bookmarks []domain.Bookmark
+
+	query := r.db.Where("user_id = ?", userID)
+	if collectionID != nil {
+		query = query.Where("collection_id = ?", *collectionID)
+	}
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+
+	err := query.
+		Preload("Post", "hidden_at IS NULL").
+		Preload("Post.OriginalPost", viewerShareableOriginal, userID, userID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&bookmarks).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+func (r *bookmarkRepository) GetBookmarkedPostIDs(userID uint64) ([]uint64, error) {
+	var postIDs []uint64
+	err := r.db.Model(&domain.Bookmark{}).
+		Where("user_id = ?", userID).
+		Pluck("post_id", &postIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return postIDs, nil
+}
+
+func (r *bookmarkRepository) CreateCollection(collection *domain.BookmarkCollection) error {
+	return r.db.Create(collection).Error
+}
+
+func (r *bookmarkRepository) GetCollection(id uint64) (*domain.BookmarkCollection, error) {
+	var collection domain.BookmarkCollection
+	if err := r.db.First(&collection, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *bookmarkRepository) ListCollections(userID uint64) ([]domain.BookmarkCollection, error) {
+	var collections []domain.BookmarkCollection
+	err := r.db.Where("user_id = ?", userID).Order("name").Find(&collections).Error
+	if err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// DeleteCollection deletes a collection and keeps its bookmarks uncollected
+func (r *bookmarkRepository) DeleteCollection(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.Bookmark{}).
+			Where("collection_id = ?", id).
+			UpdateColumn("collection_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.BookmarkCollection{}, id).Error
+	})
+}
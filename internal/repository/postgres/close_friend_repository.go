@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type closeFriendRepository struct {
+	db *gorm.DB
+}
+
+// NewCloseFriendRepository creates a new instance of CloseFriendRepository
+func NewCloseFriendRepository(db *gorm.DB) domain.CloseFriendRepository {
+	return &closeFriendRepository{db: db}
+}
+
+func (r *closeFriendRepository) Add(closeFriend *domain.CloseFriend) error {
+	return r.db.Exec(`
+		INSERT INTO close_friends (user_id, friend_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, closeFriend.UserID, closeFriend.FriendID, closeFriend.CreatedAt).Error
+}
+
+func (r *closeFriendRepository) Remove(userID, friendID uint64) error {
+	return r.db.Where("user_id = ? AND friend_id = ?", userID, friendID).Delete(&domain.CloseFriend{}).Error
+}
+
+func (r *closeFriendRepository) IsCloseFriend(userID, friendID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.CloseFriend{}).
+		Where("user_id = ? AND friend_id = ?", userID, friendID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *closeFriendRepository) List(userID uint64, page, limit int) ([]domain.User, error) {
+	var users []domain.User
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+` FROM users u
+		INNER JOIN close_friends cf ON cf.friend_id = u.id
+		WHERE cf.user_id = ? AND u.deleted_at IS NULL
+		ORDER BY cf.created_at DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"gorm.io/gorm"
@@ -35,7 +36,7 @@ func (r *commentRepository) GetByPostID(postID uint64, page, limit int) ([]domai
 	var comments []domain.Comment
 	offset := (page - 1) * limit
 
-	err := r.db.Where("post_id = ?", postID).
+	err := r.db.Where("post_id = ? AND hidden_at IS NULL", postID).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -47,10 +48,102 @@ func (r *commentRepository) GetByPostID(postID uint64, page, limit int) ([]domai
 	return comments, nil
 }
 
+// Update saves the edited content of a comment. When it changes, the replaced
+// content is kept as a revision and the edit counted.
 func (r *commentRepository) Update(comment *domain.Comment) error {
-	return r.db.Save(comment).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var editCounts []int64
+		err := tx.Raw(`
+			UPDATE comments SET edit_count = edit_count + 1
+			WHERE id = ? AND deleted_at IS NULL AND content IS DISTINCT FROM ?
+			RETURNING edit_count
+		`, comment.ID, comment.Content).Scan(&editCounts).Error
+		if err != nil {
+			return err
+		}
+
+		if len(editCounts) > 0 {
+			err = tx.Exec(`
+				INSERT INTO comment_revisions (comment_id, content, edited_at)
+				SELECT id, content, ? FROM comments WHERE id = ?
+			`, comment.UpdatedAt, comment.ID).Error
+			if err != nil {
+				return err
+			}
+			comment.EditCount = editCounts[0]
+		}
+
+		// Only the editable columns are written, moderation state may have
+		// changed since the comment was read
+		result := tx.Model(&domain.Comment{}).Where("id = ?", comment.ID).
+			Select("content", "updated_at").
+			Updates(comment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrCommentNotFound
+		}
+		return nil
+	})
 }
 
+// Delete soft deletes a comment, its revisions are kept until it is purged
 func (r *commentRepository) Delete(id uint64) error {
 	return r.db.Delete(&domain.Comment{}, id).Error
 }
+
+// SetHidden hides a comment when hiddenAt is set and restores it when nil
+func (r *commentRepository) SetHidden(id uint64, hiddenAt *time.Time) error {
+	return r.db.Model(&domain.Comment{}).Where("id = ?", id).Update("hidden_at", hiddenAt).Error
+}
+
+func (r *commentRepository) CountRecentByContent(userID uint64, content string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Comment{}).
+		Where("user_id = ? AND content = ? AND created_at >= ?", userID, content, since).
+		Count(&count).Error
+	return count, err
+}
+
+// GetRevisions returns a page of a comment's previous versions, most recent first
+func (r *commentRepository) GetRevisions(commentID uint64, page, limit int) ([]domain.CommentRevision, error) {
+	var revisions []domain.CommentRevision
+	offset := (page - 1) * limit
+
+	err := r.db.Where("comment_id = ?", commentID).
+		Order("edited_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetPurgeable returns comments soft deleted before deletedBefore, oldest first
+func (r *commentRepository) GetPurgeable(deletedBefore time.Time, limit int) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	err := r.db.Unscoped().
+		Where("deleted_at < ?", deletedBefore).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Purge permanently removes a comment with its revisions
+func (r *commentRepository) Purge(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", id).Delete(&domain.CommentRevision{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&domain.Comment{}, id).Error
+	})
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository creates a new instance of DataExportRepository
+func NewDataExportRepository(db *gorm.DB) domain.DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+func (r *dataExportRepository) Create(export *domain.DataExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *dataExportRepository) GetByID(id uint64) (*domain.DataExport, error) {
+	var export domain.DataExport
+	err := r.db.First(&export, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetActiveByUserID returns the pending or running export of a user
+func (r *dataExportRepository) GetActiveByUserID(userID uint64) (*domain.DataExport, error) {
+	var export domain.DataExport
+	err := r.db.Where("user_id = ? AND status IN ?", userID, []string{domain.DataExportPending, domain.DataExportRunning}).
+		Order("id DESC").
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) Update(export *domain.DataExport) error {
+	return r.db.Save(export).Error
+}
+
+func (r *dataExportRepository) Delete(id uint64) error {
+	return r.db.Delete(&domain.DataExport{}, id).Error
+}
+
+// Claim marks the oldest pending export as running and returns it. Running
+// exports not updated since staleBefore are claimed again, their worker is
+// assumed to be gone. Returns nil when there is nothing to claim.
+func (r *dataExportRepository) Claim(staleBefore time.Time) (*domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.db.Raw(`
+		UPDATE data_exports SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM data_exports
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, domain.DataExportRunning, time.Now(),
+		domain.DataExportPending, domain.DataExportRunning, staleBefore).Scan(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, nil
+	}
+	return &exports[0], nil
+}
+
+// GetExpired returns completed exports whose download expired before now
+func (r *dataExportRepository) GetExpired(now time.Time, limit int) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.db.Where("status = ? AND expires_at < ?", domain.DataExportCompleted, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// GetPosts returns up to limit posts of a user with an ID above afterID,
+// drafts and scheduled posts included
+func (r *dataExportRepository) GetPosts(userID, afterID uint64, limit int) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetComments returns up to limit comments of a user with an ID above afterID
+func (r *dataExportRepository) GetComments(userID, afterID uint64, limit int) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	err := r.db.Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetLikes returns up to limit likes of a user with an ID above afterID
+func (r *dataExportRepository) GetLikes(userID, afterID uint64, limit int) ([]domain.Like, error) {
+	var likes []domain.Like
+	err := r.db.Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&likes).Error
+	if err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type filterDecisionRepository struct {
+	db *gorm.DB
+}
+
+// NewFilterDecisionRepository creates a new instance of FilterDecisionRepository
+func NewFilterDecisionRepository(db *gorm.DB) domain.FilterDecisionRepository {
+	return &filterDecisionRepository{db: db}
+}
+
+func (r *filterDecisionRepository) Create(decisions []domain.FilterDecision) error {
+	return r.db.Create(&decisions).Error
+}
+
+func (r *filterDecisionRepository) AttachTarget(ids []uint64, targetID uint64) error {
+	return r.db.Model(&domain.FilterDecision{}).Where("id IN ?", ids).Update("target_id", targetID).Error
+}
+
+// List returns recorded decisions, newest first, optionally of one kind
+func (r *filterDecisionRepository) List(decision string, page, limit int) ([]domain.FilterDecision, error) {
+	var decisions []domain.FilterDecision
+	offset := (page - 1) * limit
+
+	query := r.db.Order("created_at DESC")
+	if decision != "" {
+		query = query.Where("decision = ?", decision)
+	}
+	err := query.Offset(offset).Limit(limit).Find(&decisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type followRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowRequestRepository creates a new instance of FollowRequestRepository
+func NewFollowRequestRepository(db *gorm.DB) domain.FollowRequestRepository {
+	return &followRequestRepository{db: db}
+}
+
+func (r *followRequestRepository) Create(request *domain.FollowRequest) error {
+	return r.db.Exec(`
+		INSERT INTO follow_requests (requester_id, target_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, request.RequesterID, request.TargetID, request.CreatedAt).Error
+}
+
+func (r *followRequestRepository) GetByID(id uint64) (*domain.FollowRequest, error) {
+	var request domain.FollowRequest
+	if err := r.db.First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *followRequestRepository) GetPending(targetID uint64, page, limit int) ([]domain.FollowRequest, error) {
+	var requests []domain.FollowRequest
+	offset := (page - 1) * limit
+
+	err := r.db.Where("target_id = ?", targetID).
+		Preload("Requester").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&requests).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Approve turns a request into a follow
+func (r *followRequestRepository) Approve(request *domain.FollowRequest) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO followers (follower_id, following_id)
+			VALUES (?, ?)
+			ON CONFLICT DO NOTHING
+		`, request.RequesterID, request.TargetID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.FollowRequest{}, request.ID).Error
+	})
+}
+
+// ApproveAll turns every pending request to targetID into a follow and returns the requesters
+func (r *followRequestRepository) ApproveAll(targetID uint64) ([]uint64, error) {
+	var requesterIDs []uint64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`
+			DELETE FROM follow_requests
+			WHERE target_id = ?
+			RETURNING requester_id
+		`, targetID).Scan(&requesterIDs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, requesterID := range requesterIDs {
+			err := tx.Exec(`
+				INSERT INTO followers (follower_id, following_id)
+				VALUES (?, ?)
+				ON CONFLICT DO NOTHING
+			`, requesterID, targetID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requesterIDs, nil
+}
+
+func (r *followRequestRepository) Delete(requesterID, targetID uint64) error {
+	return r.db.Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&domain.FollowRequest{}).Error
+}
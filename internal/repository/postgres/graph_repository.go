@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+// friendIDs selects the friends of a user, the accounts following the user
+// back among the accounts the user follows
+const friendIDs = `
+	SELECT f1.following_id FROM followers f1
+	INNER JOIN followers f2 ON f2.follower_id = f1.following_id AND f2.following_id = f1.follower_id
+	WHERE f1.follower_id = ?`
+
+type graphRepository struct {
+	db *gorm.DB
+}
+
+// NewGraphRepository creates a new instance of GraphRepository
+func NewGraphRepository(db *gorm.DB) domain.GraphRepository {
+	return &graphRepository{db: db}
+}
+
+// GetEdges returns the follows, follow requests, blocks, mutes and close
+// friends between userID and otherID, seen from userID
+func (r *graphRepository) GetEdges(userID, otherID uint64) (*domain.RelationshipEdges, error) {
+	var edges domain.RelationshipEdges
+	err := r.db.Raw(`
+		SELECT
+			EXISTS (SELECT 1 FROM followers WHERE follower_id = @user AND following_id = @other) AS following,
+			EXISTS (SELECT 1 FROM followers WHERE follower_id = @other AND following_id = @user) AS followed_by,
+			EXISTS (SELECT 1 FROM follow_requests WHERE requester_id = @user AND target_id = @other) AS follow_requested,
+			EXISTS (SELECT 1 FROM follow_requests WHERE requester_id = @other AND target_id = @user) AS follow_request_received,
+			EXISTS (SELECT 1 FROM blocks WHERE blocker_id = @user AND blocked_id = @other) AS blocking,
+			EXISTS (SELECT 1 FROM blocks WHERE blocker_id = @other AND blocked_id = @user) AS blocked_by,
+			EXISTS (SELECT 1 FROM mutes WHERE muter_id = @user AND muted_id = @other) AS muting,
+			EXISTS (SELECT 1 FROM close_friends WHERE user_id = @user AND friend_id = @other) AS close_friend
+	`, map[string]interface{}{"user": userID, "other": otherID}).Scan(&edges).Error
+	if err != nil {
+		return nil, err
+	}
+	return &edges, nil
+}
+
+// IsFriend reports whether both users follow each other
+func (r *graphRepository) IsFriend(userID, otherID uint64) (bool, error) {
+	var count int64
+	err := r.db.Table("followers f1").
+		Joins("INNER JOIN followers f2 ON f2.follower_id = f1.following_id AND f2.following_id = f1.follower_id").
+		Where("f1.follower_id = ? AND f1.following_id = ?", userID, otherID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetMutualFriends returns a page of the active users who are friends with
+// both users, by username
+func (r *graphRepository) GetMutualFriends(userID, otherID uint64, page, limit int) ([]domain.User, error) {
+	var users []domain.User
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+` FROM users u
+		WHERE u.deleted_at IS NULL
+			AND u.id IN (`+friendIDs+`)
+			AND u.id IN (`+friendIDs+`)
+		ORDER BY u.username
+		LIMIT ? OFFSET ?
+	`, userID, otherID, limit, offset).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *graphRepository) CountMutualFriends(userID, otherID uint64) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT COUNT(*) FROM users u
+		WHERE u.deleted_at IS NULL
+			AND u.id IN (`+friendIDs+`)
+			AND u.id IN (`+friendIDs+`)
+	`, userID, otherID).Scan(&count).Error
+	return count, err
+}
+
+// GetDegreeOfSeparation returns the number of friendships on the shortest
+// path from userID to otherID, or 0 when they are more than maxDegree
+// friendships apart. Deleted accounts do not connect users.
+func (r *graphRepository) GetDegreeOfSeparation(userID, otherID uint64, maxDegree int) (int, error) {
+	var degree sql.NullInt64
+	err := r.db.Raw(`
+		WITH RECURSIVE reachable (user_id, degree) AS (
+			SELECT CAST(@user AS BIGINT), 0
+			UNION
+			SELECT f1.following_id, r.degree + 1
+			FROM reachable r
+			INNER JOIN followers f1 ON f1.follower_id = r.user_id
+			INNER JOIN followers f2 ON f2.follower_id = f1.following_id AND f2.following_id = f1.follower_id
+			INNER JOIN users u ON u.id = f1.following_id AND u.deleted_at IS NULL
+			WHERE r.degree < @max AND r.user_id <> @other
+		)
+		SELECT MIN(degree) FROM reachable WHERE user_id = @other
+	`, map[string]interface{}{"user": userID, "other": otherID, "max": maxDegree}).Row().Scan(&degree)
+	if err != nil {
+		return 0, err
+	}
+	if !degree.Valid {
+		return 0, nil
+	}
+	return int(degree.Int64), nil
+}
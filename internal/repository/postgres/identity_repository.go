@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new instance of UserIdentityRepository
+func NewIdentityRepository(db *gorm.DB) domain.UserIdentityRepository {
+	return &identityRepository{db: db}
+}
+
+func (r *identityRepository) Create(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *identityRepository) GetByProviderSubject(provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) GetByUserID(userID uint64) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
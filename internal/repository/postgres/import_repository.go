@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importInsertBatchSize is the number of rows per INSERT statement
+const importInsertBatchSize = 100
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+// NewImportRepository creates a new instance of ImportRepository
+func NewImportRepository(db *gorm.DB) domain.ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) Create(job *domain.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importRepository) GetByID(id uint64) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importRepository) Update(job *domain.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// Claim marks the oldest pending import as running and returns it. Running
+// imports not updated since staleBefore are claimed again, their worker is
+// assumed to be gone. Returns nil when there is nothing to claim.
+func (r *importRepository) Claim(staleBefore time.Time) (*domain.ImportJob, error) {
+	var jobs []domain.ImportJob
+	err := r.db.Raw(`
+		UPDATE import_jobs SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM import_jobs
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, domain.ImportRunning, time.Now(),
+		domain.ImportPending, domain.ImportRunning, staleBefore).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// ClaimByID marks an import as running unless it is completed or running
+// elsewhere. Returns nil when the import cannot be claimed.
+func (r *importRepository) ClaimByID(id uint64, staleBefore time.Time) (*domain.ImportJob, error) {
+	var jobs []domain.ImportJob
+	err := r.db.Raw(`
+		UPDATE import_jobs SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id = ? AND (status IN (?, ?) OR (status = ? AND updated_at < ?))
+		RETURNING *
+	`, domain.ImportRunning, time.Now(), id,
+		domain.ImportPending, domain.ImportFailed, domain.ImportRunning, staleBefore).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// GetMappings returns the IDs given to the records of a kind imported from source
+func (r *importRepository) GetMappings(source, kind string, sourceIDs []string) (map[string]uint64, error) {
+	var mappings []domain.ImportMapping
+	err := r.db.Where("source = ? AND kind = ? AND source_id IN ?", source, kind, sourceIDs).
+		Find(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	targetIDs := make(map[string]uint64, len(mappings))
+	for _, mapping := range mappings {
+		targetIDs[mapping.SourceID] = mapping.TargetID
+	}
+	return targetIDs, nil
+}
+
+// GetTakenUsernamesAndEmails returns which of the usernames and emails are
+// used by an account, deleted accounts included. Former usernames still
+// reserved for their owner are taken too.
+func (r *importRepository) GetTakenUsernamesAndEmails(usernames, emails []string) (map[string]bool, error) {
+	var users []domain.User
+	err := r.db.Unscoped().
+		Select("username", "email").
+		Where("username IN ? OR email IN ?", usernames, emails).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(users)*2)
+	for _, user := range users {
+		taken[user.Username] = true
+		taken[user.Email] = true
+	}
+
+	var reserved []string
+	err = r.db.Model(&domain.UsernameChange{}).
+		Where("username IN ? AND reserved_until > ?", usernames, time.Now()).
+		Pluck("username", &reserved).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, username := range reserved {
+		taken[username] = true
+	}
+	return taken, nil
+}
+
+// ImportUsers inserts users with their mappings and saves the job checkpoint
+// in one transaction
+func (r *importRepository) ImportUsers(job *domain.ImportJob, users []domain.User, sourceIDs []string) error {
+	return r.importBatch(job, domain.ImportKindUser, &users, sourceIDs, func() []uint64 {
+		ids := make([]uint64, len(users))
+		for i, user := range users {
+			ids[i] = user.ID
+		}
+		return ids
+	})
+}
+
+func (r *importRepository) ImportPosts(job *domain.ImportJob, posts []domain.Post, sourceIDs []string) error {
+	return r.importBatch(job, domain.ImportKindPost, &posts, sourceIDs, func() []uint64 {
+		ids := make([]uint64, len(posts))
+		for i, post := range posts {
+			ids[i] = post.ID
+		}
+		return ids
+	})
+}
+
+func (r *importRepository) ImportComments(job *domain.ImportJob, comments []domain.Comment, sourceIDs []string) error {
+	return r.importBatch(job, domain.ImportKindComment, &comments, sourceIDs, func() []uint64 {
+		ids := make([]uint64, len(comments))
+		for i, comment := range comments {
+			ids[i] = comment.ID
+		}
+		return ids
+	})
+}
+
+func (r *importRepository) ImportLikes(job *domain.ImportJob, likes []domain.Like, sourceIDs []string) error {
+	return r.importBatch(job, domain.ImportKindLike, &likes, sourceIDs, func() []uint64 {
+		ids := make([]uint64, len(likes))
+		for i, like := range likes {
+			ids[i] = like.ID
+		}
+		return ids
+	})
+}
+
+// ImportFollows inserts follows, keeping the ones that already exist, and
+// saves the job checkpoint in one transaction
+func (r *importRepository) ImportFollows(job *domain.ImportJob, follows []domain.Follow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(follows) > 0 {
+			err := tx.Table("followers").
+				Clauses(clause.OnConflict{DoNothing: true}).
+				CreateInBatches(&follows, importInsertBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Save(job).Error
+	})
+}
+
+// importBatch inserts rows, maps sourceIDs to the IDs the rows were given
+// and saves the job checkpoint in one transaction, so that a resumed import
+// never inserts a row twice
+func (r *importRepository) importBatch(job *domain.ImportJob, kind string, rows interface{}, sourceIDs []string, targetIDs func() []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(sourceIDs) > 0 {
+			if err := tx.CreateInBatches(rows, importInsertBatchSize).Error; err != nil {
+				return err
+			}
+
+			ids := targetIDs()
+			mappings := make([]domain.ImportMapping, len(sourceIDs))
+			for i, sourceID := range sourceIDs {
+				mappings[i] = domain.ImportMapping{
+					Source:   job.Source,
+					Kind:     kind,
+					SourceID: sourceID,
+					TargetID: ids[i],
+				}
+			}
+			if err := tx.CreateInBatches(&mappings, importInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(job).Error
+	})
+}
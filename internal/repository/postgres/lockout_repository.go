@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type loginLockoutRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginLockoutRepository creates a new instance of LoginLockoutRepository
+func NewLoginLockoutRepository(db *gorm.DB) domain.LoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+func (r *loginLockoutRepository) Create(lockout *domain.LoginLockout) error {
+	return r.db.Create(lockout).Error
+}
+
+func (r *loginLockoutRepository) List(page, limit int) ([]domain.LoginLockout, error) {
+	var lockouts []domain.LoginLockout
+	offset := (page - 1) * limit
+
+	err := r.db.Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&lockouts).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return lockouts, nil
+}
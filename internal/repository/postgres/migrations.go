@@ -43,5 +43,28 @@ func RunMigrations(db *gorm.DB) error {
 		&domain.Post{},
 		&domain.Comment{},
 		&domain.Like{},
+		&domain.Webhook{},
+		&domain.WebhookDelivery{},
+		&domain.VerificationToken{},
+		&domain.LoginLockout{},
+		&domain.RecoveryCode{},
+		&domain.UserIdentity{},
+		&domain.Report{},
+		&domain.ModerationAction{},
+		&domain.FilterDecision{},
+		&domain.Block{},
+		&domain.Mute{},
+		&domain.FollowRequest{},
+		&domain.CloseFriend{},
+		&domain.BookmarkCollection{},
+		&domain.Bookmark{},
+		&domain.PostRevision{},
+		&domain.CommentRevision{},
+		&domain.AccountDeletion{},
+		&domain.DataExport{},
+		&domain.ImportJob{},
+		&domain.ImportMapping{},
+		&domain.UsernameChange{},
+		&domain.PrivacySettings{},
 	)
 }
@@ -2,11 +2,38 @@ package postgres
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"gorm.io/gorm"
 )
 
+// shareableOriginal matches posts that reposts and quotes may still show: not
+// hidden or deleted, public and written by a public account
+const shareableOriginal = "hidden_at IS NULL AND deleted_at IS NULL AND visibility = '" + domain.PostVisibilityPublic + "' AND user_id IN (SELECT id FROM users WHERE is_private = false AND deleted_at IS NULL)"
+
+// liveShare leaves out reposts whose original is no longer shareable
+const liveShare = "(original_post_id IS NULL OR content <> '' OR original_post_id IN (SELECT id FROM posts WHERE " + shareableOriginal + "))"
+
+// blockedWithViewer selects the users who blocked or were blocked by the
+// viewer. The viewer ID is bound twice.
+const blockedWithViewer = "SELECT blocked_id FROM blocks WHERE blocker_id = ? UNION SELECT blocker_id FROM blocks WHERE blocked_id = ?"
+
+// viewerShareableOriginal narrows shareableOriginal to originals whose author
+// has no block with the viewer
+const viewerShareableOriginal = shareableOriginal + " AND user_id NOT IN (" + blockedWithViewer + ")"
+
+// feedShareableOriginal also leaves out originals of users the viewer muted,
+// as the newsfeed does for their own posts. The viewer ID is bound three times.
+const feedShareableOriginal = shareableOriginal + " AND user_id NOT IN (" + blockedWithViewer + " UNION SELECT muted_id FROM mutes WHERE muter_id = ?)"
+
+// feedLiveShare leaves out reposts whose original the viewer cannot see in
+// their newsfeed
+const feedLiveShare = "(original_post_id IS NULL OR content <> '' OR original_post_id IN (SELECT id FROM posts WHERE " + feedShareableOriginal + "))"
+
+// pendingStatuses are the statuses of posts that have not been published yet
+var pendingStatuses = []string{domain.PostStatusDraft, domain.PostStatusScheduled}
+
 type postRepository struct {
 	db *gorm.DB
 }
@@ -16,13 +43,29 @@ func NewPostRepository(db *gorm.DB) domain.PostRepository {
 	return &postRepository{db: db}
 }
 
+// Create stores a post and counts it as a share of its original
 func (r *postRepository) Create(post *domain.Post) error {
-	return r.db.Create(post).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("OriginalPost").Create(post).Error; err != nil {
+			return err
+		}
+		if post.OriginalPostID == nil {
+			return nil
+		}
+
+		return tx.Model(&domain.Post{}).
+			Where("id = ?", *post.OriginalPostID).
+			UpdateColumn("share_count", gorm.Expr("share_count + 1")).Error
+	})
 }
 
 func (r *postRepository) GetByID(id uint64) (*domain.Post, error) {
 	var post domain.Post
-	if err := r.db.Preload("Likes").Preload("Comments").First(&post, id).Error; err != nil {
+	err := r.db.Preload("Likes").
+		Preload("Comments", "hidden_at IS NULL").
+		Preload("OriginalPost", shareableOriginal).
+		First(&post, id).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -31,13 +74,17 @@ func (r *postRepository) GetByID(id uint64) (*domain.Post, error) {
 	return &post, nil
 }
 
-func (r *postRepository) GetByUserID(userID uint64, page, limit int) ([]domain.Post, error) {
+// GetByUserID returns a page of a user's posts with one of the given visibilities
+func (r *postRepository) GetByUserID(userID uint64, visibilities []string, page, limit int) ([]domain.Post, error) {
 	var posts []domain.Post
 	offset := (page - 1) * limit
 
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.Where("user_id = ? AND visibility IN ? AND hidden_at IS NULL", userID, visibilities).
+		Where("status = ?", domain.PostStatusPublished).
+		Where(liveShare).
 		Preload("Likes").
-		Preload("Comments").
+		Preload("Comments", "hidden_at IS NULL").
+		Preload("OriginalPost", shareableOriginal).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -49,52 +96,381 @@ func (r *postRepository) GetByUserID(userID uint64, page, limit int) ([]domain.P
 	return posts, nil
 }
 
+// Update saves the edited fields of a post. When its content or image
+// changes, the replaced version is kept as a revision and the edit counted.
 func (r *postRepository) Update(post *domain.Post) error {
-	return r.db.Save(post).Error
-}
-
-func (r *postRepository) Delete(id uint64) error {
-	// Start a transaction to delete post and related data
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Delete likes
-		if err := tx.Where("post_id = ?", id).Delete(&domain.Like{}).Error; err != nil {
+		var editCounts []int64
+		err := tx.Raw(`
+			UPDATE posts SET edit_count = edit_count + 1
+			WHERE id = ? AND deleted_at IS NULL
+				AND (content IS DISTINCT FROM ? OR image_url IS DISTINCT FROM ?)
+			RETURNING edit_count
+		`, post.ID, post.Content, post.ImageURL).Scan(&editCounts).Error
+		if err != nil {
 			return err
 		}
 
-		// Delete comments
-		if err := tx.Where("post_id = ?", id).Delete(&domain.Comment{}).Error; err != nil {
-			return err
+		if len(editCounts) > 0 {
+			err = tx.Exec(`
+				INSERT INTO post_revisions (post_id, content, image_url, edited_at)
+				SELECT id, content, image_url, ? FROM posts WHERE id = ?
+			`, post.UpdatedAt, post.ID).Error
+			if err != nil {
+				return err
+			}
+			post.EditCount = editCounts[0]
 		}
 
-		// Delete post
-		if err := tx.Delete(&domain.Post{}, id).Error; err != nil {
+		// Only the editable columns are written, moderation and counters may
+		// have changed since the post was read
+		result := tx.Model(&domain.Post{}).Where("id = ?", post.ID).
+			Select("content", "image_url", "visibility", "updated_at").
+			Updates(post)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPostNotFound
+		}
+		return nil
+	})
+}
+
+// Delete soft deletes a post together with its reposts, so that they are
+// restored together. Likes, comments and bookmarks are kept until the post is purged.
+func (r *postRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Uncount the share on the original
+		err := tx.Exec(`
+			UPDATE posts SET share_count = share_count - 1
+			WHERE id = (SELECT original_post_id FROM posts WHERE id = ? AND deleted_at IS NULL)
+		`, id).Error
+		if err != nil {
 			return err
 		}
 
-		return nil
+		return tx.Model(&domain.Post{}).
+			Where("id = ? OR (original_post_id = ? AND content = '')", id, id).
+			UpdateColumn("deleted_at", time.Now()).Error
 	})
 }
 
+// GetNewsFeed returns the user's posts and those of followed users shared with
+// followers or with the user as a close friend, leaving out muted users.
+// Blocked users never appear because blocking removes the follows. A post
+// reposted by several followees, or seen both directly and reposted, only
+// appears once as its latest share. Shares of posts by users blocked with or
+// muted by the user show without their original, or not at all for reposts.
 func (r *postRepository) GetNewsFeed(userID uint64, page, limit int) ([]domain.Post, error) {
 	var posts []domain.Post
 	offset := (page - 1) * limit
 
 	err := r.db.Raw(`
-		SELECT DISTINCT p.* FROM posts p
-		INNER JOIN followers f ON f.following_id = p.user_id
-		WHERE f.follower_id = ?
-		UNION
-		SELECT p.* FROM posts p
-		WHERE p.user_id = ?
+		SELECT * FROM (
+			SELECT DISTINCT ON (feed.share_key) feed.* FROM (
+				SELECT p.*, CASE WHEN p.original_post_id IS NOT NULL AND p.content = ''
+					THEN p.original_post_id ELSE p.id END AS share_key
+				FROM posts p
+				INNER JOIN followers f ON f.following_id = p.user_id
+				WHERE f.follower_id = ? AND p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = ?
+				AND p.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = ?)
+				AND (
+					p.visibility IN (?, ?)
+					OR (p.visibility = ? AND EXISTS (
+						SELECT 1 FROM close_friends cf WHERE cf.user_id = p.user_id AND cf.friend_id = ?
+					))
+				)
+				UNION
+				SELECT p.*, CASE WHEN p.original_post_id IS NOT NULL AND p.content = ''
+					THEN p.original_post_id ELSE p.id END AS share_key
+				FROM posts p
+				WHERE p.user_id = ? AND p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = ?
+			) feed
+			WHERE `+feedLiveShare+`
+			ORDER BY feed.share_key, feed.created_at DESC
+		) deduplicated
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
-	`, userID, userID, limit, offset).
+	`, userID, domain.PostStatusPublished, userID,
+		domain.PostVisibilityPublic, domain.PostVisibilityFollowers,
+		domain.PostVisibilityCloseFriends, userID,
+		userID, domain.PostStatusPublished,
+		userID, userID, userID, limit, offset).
 		Preload("Likes").
-		Preload("Comments").
+		Preload("Comments", "hidden_at IS NULL").
+		Preload("OriginalPost", feedShareableOriginal, userID, userID, userID).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// SetHidden hides a post from feeds when hiddenAt is set and restores it when nil
+func (r *postRepository) SetHidden(id uint64, hiddenAt *time.Time) error {
+	return r.db.Model(&domain.Post{}).Where("id = ?", id).Update("hidden_at", hiddenAt).Error
+}
+
+func (r *postRepository) CountRecentByContent(userID uint64, content string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Post{}).
+		Where("user_id = ? AND content = ? AND created_at >= ?", userID, content, since).
+		Where("status = ?", domain.PostStatusPublished).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *postRepository) GetRepost(userID, originalPostID uint64) (*domain.Post, error) {
+	var post domain.Post
+	err := r.db.Where("user_id = ? AND original_post_id = ? AND content = ''", userID, originalPostID).First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// IsShareable reports whether reposts and quotes may still show a post
+func (r *postRepository) IsShareable(id uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Post{}).Where("id = ?", id).Where(shareableOriginal).Count(&count).Error
+	return count > 0, err
+}
+
+// GetSharesByAuthor returns the IDs and authors of the reposts and quotes of
+// any post of a user
+func (r *postRepository) GetSharesByAuthor(authorID uint64) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Select("id", "user_id").
+		Where("original_post_id IN (SELECT id FROM posts WHERE user_id = ?)", authorID).
 		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
 
+// GetShares returns the IDs and authors of the reposts and quotes of a post
+func (r *postRepository) GetShares(originalPostID uint64) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Select("id", "user_id").
+		Where("original_post_id = ?", originalPostID).
+		Find(&posts).Error
 	if err != nil {
 		return nil, err
 	}
 	return posts, nil
 }
+
+// GetDrafts returns a page of a user's drafts and scheduled posts, most recently edited first
+func (r *postRepository) GetDrafts(userID uint64, page, limit int) ([]domain.Post, error) {
+	var posts []domain.Post
+	offset := (page - 1) * limit
+
+	err := r.db.Where("user_id = ? AND status IN ?", userID, pendingStatuses).
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// UpdateDraft updates a post unless it has been published meanwhile, and
+// reports whether it was updated
+func (r *postRepository) UpdateDraft(post *domain.Post) (bool, error) {
+	result := r.db.Model(&domain.Post{}).
+		Where("id = ? AND status IN ?", post.ID, pendingStatuses).
+		Select("content", "image_url", "visibility", "status", "publish_at", "updated_at").
+		Updates(post)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteDraft deletes a post unless it has been published meanwhile, and
+// reports whether it was deleted. Unpublished posts have no likes, comments,
+// bookmarks or shares to clean up.
+func (r *postRepository) DeleteDraft(id uint64) (bool, error) {
+	result := r.db.Where("id = ? AND status IN ?", id, pendingStatuses).Delete(&domain.Post{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// PublishDraft publishes a draft or scheduled post dated publishedAt. It
+// returns nil if the post was not pending, e.g. the scheduler got to it first.
+func (r *postRepository) PublishDraft(id uint64, publishedAt time.Time) (*domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Raw(`
+		UPDATE posts SET status = ?, publish_at = ?, created_at = ?, updated_at = ?
+		WHERE id = ? AND status IN ? AND deleted_at IS NULL
+		RETURNING *
+	`, domain.PostStatusPublished, publishedAt, publishedAt, publishedAt, id, pendingStatuses).Scan(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, nil
+	}
+	return &posts[0], nil
+}
+
+// PublishDue publishes up to limit scheduled posts whose time has come and
+// returns them. Rows locked by another instance are skipped and the status
+// only changes once, so every post is returned to exactly one caller.
+func (r *postRepository) PublishDue(now time.Time, limit int) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Raw(`
+		UPDATE posts SET status = ?, created_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = ? AND publish_at <= ? AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, domain.PostStatusPublished, now, now, domain.PostStatusScheduled, now, limit).Scan(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetRevisions returns a page of a post's previous versions, most recent first
+func (r *postRepository) GetRevisions(postID uint64, page, limit int) ([]domain.PostRevision, error) {
+	var revisions []domain.PostRevision
+	offset := (page - 1) * limit
+
+	err := r.db.Where("post_id = ?", postID).
+		Order("edited_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetDeleted returns a soft deleted post, or nil if the post is not deleted
+func (r *postRepository) GetDeleted(id uint64) (*domain.Post, error) {
+	var post domain.Post
+	err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// Restore undeletes a soft deleted post and the reposts deleted with it
+func (r *postRepository) Restore(post *domain.Post) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&domain.Post{}).
+			Where("id = ? OR (original_post_id = ? AND content = '')", post.ID, post.ID).
+			Where("deleted_at = ?", post.DeletedAt.Time).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		// A concurrent restore got there first
+		if result.RowsAffected == 0 {
+			return domain.ErrPostNotFound
+		}
+		if post.OriginalPostID == nil {
+			return nil
+		}
+
+		// Count the share on the original again
+		return tx.Unscoped().Model(&domain.Post{}).
+			Where("id = ?", *post.OriginalPostID).
+			UpdateColumn("share_count", gorm.Expr("share_count + 1")).Error
+	})
+}
+
+// GetPurgeable returns posts soft deleted before deletedBefore, oldest first
+func (r *postRepository) GetPurgeable(deletedBefore time.Time, limit int) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := r.db.Unscoped().
+		Where("deleted_at < ?", deletedBefore).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetMediaURLs returns the images of a post and of its revisions
+func (r *postRepository) GetMediaURLs(id uint64) ([]string, error) {
+	var urls []string
+	err := r.db.Raw(`
+		SELECT image_url FROM posts WHERE id = ? AND image_url <> ''
+		UNION
+		SELECT image_url FROM post_revisions WHERE post_id = ? AND image_url <> ''
+	`, id, id).Scan(&urls).Error
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// Purge permanently removes a post with its likes, comments, bookmarks,
+// revisions and reposts. Quote posts of it are kept without the reference.
+func (r *postRepository) Purge(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Unscoped().Session(&gorm.Session{})
+		reposts := tx.Model(&domain.Post{}).Select("id").Where("original_post_id = ? AND content = ''", id)
+
+		// Delete likes
+		if err := tx.Where("post_id = ? OR post_id IN (?)", id, reposts).Delete(&domain.Like{}).Error; err != nil {
+			return err
+		}
+
+		// Delete comments with their revisions
+		comments := tx.Model(&domain.Comment{}).Select("id").Where("post_id = ? OR post_id IN (?)", id, reposts)
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&domain.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ? OR post_id IN (?)", id, reposts).Delete(&domain.Comment{}).Error; err != nil {
+			return err
+		}
+
+		// Delete revisions
+		if err := tx.Where("post_id = ?", id).Delete(&domain.PostRevision{}).Error; err != nil {
+			return err
+		}
+
+		// Delete bookmarks
+		if err := tx.Where("post_id = ? OR post_id IN (?)", id, reposts).Delete(&domain.Bookmark{}).Error; err != nil {
+			return err
+		}
+
+		// Delete reposts and detach quotes
+		if err := tx.Where("original_post_id = ? AND content = ''", id).Delete(&domain.Post{}).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&domain.Post{}).
+			Where("original_post_id = ?", id).
+			UpdateColumn("original_post_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		// Delete post
+		if err := tx.Delete(&domain.Post{}, id).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
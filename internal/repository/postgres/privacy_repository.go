@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new instance of PrivacyRepository
+func NewPrivacyRepository(db *gorm.DB) domain.PrivacyRepository {
+	return &privacyRepository{db: db}
+}
+
+func (r *privacyRepository) GetByUserID(userID uint64) (*domain.PrivacySettings, error) {
+	var settings domain.PrivacySettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Save creates or replaces the settings of a user
+func (r *privacyRepository) Save(settings *domain.PrivacySettings) error {
+	return r.db.Save(settings).Error
+}
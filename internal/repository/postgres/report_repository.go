@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new instance of ReportRepository
+func NewReportRepository(db *gorm.DB) domain.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create relies on the unique reporter and target index, so concurrent
+// duplicate reports cannot both be stored
+func (r *reportRepository) Create(report *domain.Report) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAlreadyReported
+	}
+	return nil
+}
+
+func (r *reportRepository) Exists(reporterID uint64, targetType string, targetID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Report{}).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ?", reporterID, targetType, targetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *reportRepository) CountOpen(targetType string, targetID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, domain.ReportStatusOpen).
+		Count(&count).Error
+	return count, err
+}
+
+// GetQueue returns targets with open reports, most reported first and then
+// oldest first so that nothing waits forever
+func (r *reportRepository) GetQueue(page, limit int) ([]domain.ModerationQueueItem, error) {
+	var items []domain.ModerationQueueItem
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT target_type, target_id, COUNT(*) AS report_count,
+			MIN(created_at) AS first_reported_at, MAX(created_at) AS last_reported_at
+		FROM reports
+		WHERE status = ?
+		GROUP BY target_type, target_id
+		ORDER BY report_count DESC, first_reported_at ASC
+		LIMIT ? OFFSET ?
+	`, domain.ReportStatusOpen, limit, offset).Scan(&items).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *reportRepository) GetByTarget(targetType string, targetID uint64) ([]domain.Report, error) {
+	var reports []domain.Report
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at DESC").
+		Find(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ResolveTarget closes all open reports of a target with the given status
+func (r *reportRepository) ResolveTarget(targetType string, targetID uint64, status string, moderatorID uint64) error {
+	return r.db.Model(&domain.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, domain.ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": moderatorID,
+			"resolved_at": time.Now(),
+		}).Error
+}
+
+func (r *reportRepository) CreateAction(action *domain.ModerationAction) error {
+	return r.db.Create(action).Error
+}
+
+func (r *reportRepository) GetActionsByUser(userID uint64, action string, page, limit int) ([]domain.ModerationAction, error) {
+	var actions []domain.ModerationAction
+	offset := (page - 1) * limit
+
+	err := r.db.Where("target_user_id = ? AND action = ?", userID, action).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&actions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+// suggestableUser holds for the users u that may be suggested to @user:
+// active accounts other than @user that @user neither follows, asked to
+// follow nor blocks, and that do not block @user
+const suggestableUser = `
+	u.deleted_at IS NULL AND u.suspended_at IS NULL AND u.id <> @user
+	AND NOT EXISTS (
+		SELECT 1 FROM followers x WHERE x.follower_id = @user AND x.following_id = u.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM follow_requests x WHERE x.requester_id = @user AND x.target_id = u.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM blocks x
+		WHERE (x.blocker_id = @user AND x.blocked_id = u.id)
+			OR (x.blocker_id = u.id AND x.blocked_id = @user)
+	)`
+
+type suggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository creates a new instance of SuggestionRepository
+func NewSuggestionRepository(db *gorm.DB) domain.SuggestionRepository {
+	return &suggestionRepository{db: db}
+}
+
+// GetFriendsOfFriends returns the accounts followed by the accounts userID
+// follows, ranked by how many of them follow each account
+func (r *suggestionRepository) GetFriendsOfFriends(userID uint64, limit int) ([]domain.SuggestionCandidate, error) {
+	var candidates []domain.SuggestionCandidate
+	err := r.db.Raw(`
+		SELECT u.id AS user_id, COUNT(*) AS mutual_count
+		FROM followers f1
+		INNER JOIN users m ON m.id = f1.following_id AND m.deleted_at IS NULL
+		INNER JOIN followers f2 ON f2.follower_id = f1.following_id
+		INNER JOIN users u ON u.id = f2.following_id
+		WHERE f1.follower_id = @user AND `+suggestableUser+`
+		GROUP BY u.id
+		ORDER BY mutual_count DESC, u.id
+		LIMIT @limit
+	`, map[string]interface{}{"user": userID, "limit": limit}).Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// GetPopular returns the accounts with the most followers that may be
+// suggested to userID, except excludeIDs
+func (r *suggestionRepository) GetPopular(userID uint64, excludeIDs []uint64, limit int) ([]uint64, error) {
+	args := map[string]interface{}{"user": userID, "limit": limit}
+	exclude := ""
+	if len(excludeIDs) > 0 {
+		exclude = "AND u.id NOT IN @exclude"
+		args["exclude"] = excludeIDs
+	}
+
+	var ids []uint64
+	err := r.db.Raw(`
+		SELECT u.id FROM users u
+		INNER JOIN followers f ON f.following_id = u.id
+		WHERE `+suggestableUser+` `+exclude+`
+		GROUP BY u.id
+		ORDER BY COUNT(*) DESC, u.id
+		LIMIT @limit
+	`, args).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// mutualPreviewRow is an account userID follows that follows a candidate
+type mutualPreviewRow struct {
+	CandidateID uint64
+	domain.User
+}
+
+// GetMutualPreviews returns, for each candidate, up to perCandidate accounts
+// userID follows that follow the candidate, most recently followed first
+func (r *suggestionRepository) GetMutualPreviews(userID uint64, candidateIDs []uint64, perCandidate int) (map[uint64][]domain.User, error) {
+	previews := make(map[uint64][]domain.User, len(candidateIDs))
+	if len(candidateIDs) == 0 {
+		return previews, nil
+	}
+
+	var rows []mutualPreviewRow
+	err := r.db.Raw(`
+		SELECT m.candidate_id, `+listUserColumns+` FROM (
+			SELECT f2.following_id AS candidate_id, f1.following_id AS mutual_id,
+				ROW_NUMBER() OVER (PARTITION BY f2.following_id ORDER BY f1.created_at DESC, f1.following_id) AS rn
+			FROM followers f1
+			INNER JOIN users v ON v.id = f1.following_id AND v.deleted_at IS NULL
+			INNER JOIN followers f2 ON f2.follower_id = f1.following_id
+			WHERE f1.follower_id = @user AND f2.following_id IN @candidates
+		) m
+		INNER JOIN users u ON u.id = m.mutual_id
+		WHERE m.rn <= @limit
+		ORDER BY m.candidate_id, m.rn
+	`, map[string]interface{}{"user": userID, "candidates": candidateIDs, "limit": perCandidate}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		previews[row.CandidateID] = append(previews[row.CandidateID], row.User)
+	}
+	return previews, nil
+}
+
+// GetUsers returns the active users among ids, in no particular order
+func (r *suggestionRepository) GetUsers(ids []uint64) ([]domain.User, error) {
+	var users []domain.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+` FROM users u
+		WHERE u.id IN ? AND u.deleted_at IS NULL
+	`, ids).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FilterSuggestable returns the candidates that may still be suggested to
+// userID, e.g. dropping the ones followed or blocked since the suggestions
+// were computed
+func (r *suggestionRepository) FilterSuggestable(userID uint64, candidateIDs []uint64) ([]uint64, error) {
+	var ids []uint64
+	if len(candidateIDs) == 0 {
+		return ids, nil
+	}
+
+	err := r.db.Raw(`
+		SELECT u.id FROM users u
+		WHERE u.id IN @candidates AND `+suggestableUser,
+		map[string]interface{}{"user": userID, "candidates": candidateIDs}).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetUserIDs returns the IDs of active users after afterID, in ID order
+func (r *suggestionRepository) GetUserIDs(afterID uint64, limit int) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&domain.User{}).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepository
+func NewTwoFactorRepository(db *gorm.DB) domain.TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID uint64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		codes := make([]domain.RecoveryCode, len(codeHashes))
+		for i, codeHash := range codeHashes {
+			codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: codeHash, CreatedAt: now}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode consumes an unused recovery code. It reports false if no such code exists.
+func (r *twoFactorRepository) UseRecoveryCode(userID uint64, codeHash string) (bool, error) {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepository) DeleteRecoveryCodes(userID uint64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
+
+// AdvanceCounter records the time step of an accepted TOTP code. It reports
+// false if the same or a later step was already used, which rejects replays.
+func (r *twoFactorRepository) AdvanceCounter(userID uint64, counter int64) (bool, error) {
+	result := r.db.Model(&domain.User{}).
+		Where("id = ? AND two_factor_last_counter < ?", userID, counter).
+		Update("two_factor_last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
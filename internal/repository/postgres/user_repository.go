@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"gorm.io/gorm"
@@ -53,20 +54,105 @@ func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+// GetByFormerUsername returns the user who most recently gave up username,
+// as long as the name is still reserved for them
+func (r *userRepository) GetByFormerUsername(username string, now time.Time) (*domain.User, error) {
+	var user domain.User
+	err := r.db.
+		Joins("INNER JOIN username_changes uc ON uc.user_id = users.id").
+		Where("uc.username = ? AND uc.reserved_until > ?", username, now).
+		Order("uc.created_at DESC").
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// IsUsernameTaken reports whether username belongs to an account other than
+// userID, deleted accounts included, or is still reserved for one
+func (r *userRepository) IsUsernameTaken(username string, userID uint64, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.User{}).
+		Where("username = ? AND id <> ?", username, userID).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&domain.UsernameChange{}).
+		Where("username = ? AND user_id <> ? AND reserved_until > ?", username, userID, now).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ChangeUsername saves a renamed user and reserves the former username in
+// one transaction. A reservation of the new name by the same user, who is
+// taking a former name back, is released.
+func (r *userRepository) ChangeUsername(user *domain.User, change *domain.UsernameChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND username = ?", user.ID, user.Username).
+			Delete(&domain.UsernameChange{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return updateUserColumns(tx, user, "username", "username_changed_at")
+	})
+}
+
+// Update writes the named columns of user together with updated_at. Other
+// columns keep their stored values, so concurrent changes such as a
+// suspension or the 2FA state are not overwritten from a stale read.
+func (r *userRepository) Update(user *domain.User, columns ...string) error {
+	return updateUserColumns(r.db, user, columns...)
+}
+
+func updateUserColumns(db *gorm.DB, user *domain.User, columns ...string) error {
+	if len(columns) == 0 {
+		return errors.New("no columns to update")
+	}
+	result := db.Model(&domain.User{}).Where("id = ?", user.ID).
+		Select(append(columns, "updated_at")).
+		Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
 }
 
 func (r *userRepository) Delete(id uint64) error {
 	return r.db.Delete(&domain.User{}, id).Error
 }
 
+func (r *userRepository) List(page, limit int) ([]domain.User, error) {
+	var users []domain.User
+	offset := (page - 1) * limit
+	err := r.db.Order("id ASC").Offset(offset).Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// listUserColumns are the columns of users shown in user lists, the
+// password hash and account secrets are never read
+const listUserColumns = "u.id, u.username, u.first_name, u.last_name, u.avatar_url, u.is_private, u.created_at"
+
 func (r *userRepository) GetFollowers(userID uint64) ([]domain.User, error) {
 	var users []domain.User
 	err := r.db.Raw(`
-		SELECT u.* FROM users u
+		SELECT `+listUserColumns+` FROM users u
 		INNER JOIN followers f ON f.follower_id = u.id
-		WHERE f.following_id = ?
+		WHERE f.following_id = ? AND u.deleted_at IS NULL
 	`, userID).Scan(&users).Error
 	if err != nil {
 		return nil, err
@@ -77,9 +163,9 @@ func (r *userRepository) GetFollowers(userID uint64) ([]domain.User, error) {
 func (r *userRepository) GetFollowing(userID uint64) ([]domain.User, error) {
 	var users []domain.User
 	err := r.db.Raw(`
-		SELECT u.* FROM users u
+		SELECT `+listUserColumns+` FROM users u
 		INNER JOIN followers f ON f.following_id = u.id
-		WHERE f.follower_id = ?
+		WHERE f.follower_id = ? AND u.deleted_at IS NULL
 	`, userID).Scan(&users).Error
 	if err != nil {
 		return nil, err
@@ -87,6 +173,91 @@ func (r *userRepository) GetFollowing(userID uint64) ([]domain.User, error) {
 	return users, nil
 }
 
+// GetFollowersPage returns a page of the followers of a user, most recent
+// follow first, flagged with whether viewerID follows them and they follow
+// viewerID
+func (r *userRepository) GetFollowersPage(userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error) {
+	return r.getFollowPage("f.follower_id", "f.following_id", userID, viewerID, page, limit)
+}
+
+// GetFollowingPage returns a page of the users a user follows, most recent
+// follow first, flagged like GetFollowersPage
+func (r *userRepository) GetFollowingPage(userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error) {
+	return r.getFollowPage("f.following_id", "f.follower_id", userID, viewerID, page, limit)
+}
+
+func (r *userRepository) getFollowPage(userColumn, ownerColumn string, userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error) {
+	var entries []domain.FollowListEntry
+	offset := (page - 1) * limit
+
+	err := r.db.Raw(`
+		SELECT `+listUserColumns+`, f.created_at AS followed_at,
+			EXISTS (
+				SELECT 1 FROM followers v WHERE v.follower_id = ? AND v.following_id = u.id
+			) AS followed_by_me,
+			EXISTS (
+				SELECT 1 FROM followers v WHERE v.follower_id = u.id AND v.following_id = ?
+			) AS follows_me
+		FROM users u
+		INNER JOIN followers f ON `+userColumn+` = u.id
+		WHERE `+ownerColumn+` = ? AND u.deleted_at IS NULL
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT ? OFFSET ?
+	`, viewerID, viewerID, userID, limit, offset).Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CountFollows counts the followers and followed accounts of a user, deleted
+// accounts excluded
+func (r *userRepository) CountFollows(userID uint64) (*domain.FollowCounts, error) {
+	var counts domain.FollowCounts
+	err := r.db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM followers f
+				INNER JOIN users u ON u.id = f.follower_id
+				WHERE f.following_id = ? AND u.deleted_at IS NULL) AS followers,
+			(SELECT COUNT(*) FROM followers f
+				INNER JOIN users u ON u.id = f.following_id
+				WHERE f.follower_id = ? AND u.deleted_at IS NULL) AS following
+	`, userID, userID).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// CountPosts counts the published posts of a user, hidden ones excluded
+func (r *userRepository) CountPosts(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Post{}).
+		Where("user_id = ? AND status = ? AND hidden_at IS NULL", userID, domain.PostStatusPublished).
+		Count(&count).Error
+	return count, err
+}
+
+// GetMediaURLs returns the profile images of a user, deleted users included
+func (r *userRepository) GetMediaURLs(id uint64) ([]string, error) {
+	var user domain.User
+	err := r.db.Unscoped().Select("avatar_url", "cover_url").First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var urls []string
+	for _, url := range []string{user.AvatarURL, user.CoverURL} {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
 func (r *userRepository) Follow(followerID, followingID uint64) error {
 	return r.db.Exec(`
 		INSERT INTO followers (follower_id, following_id)
@@ -101,3 +272,30 @@ func (r *userRepository) Unfollow(followerID, followingID uint64) error {
 		WHERE follower_id = ? AND following_id = ?
 	`, followerID, followingID).Error
 }
+
+func (r *userRepository) IsFollowing(followerID, followingID uint64) (bool, error) {
+	var count int64
+	err := r.db.Table("followers").
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPurgeable returns users soft deleted before deletedBefore, oldest first
+func (r *userRepository) GetPurgeable(deletedBefore time.Time, limit int) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Unscoped().
+		Where("deleted_at < ?", deletedBefore).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Purge permanently removes a soft deleted user
+func (r *userRepository) Purge(id uint64) error {
+	return r.db.Unscoped().Delete(&domain.User{}, id).Error
+}
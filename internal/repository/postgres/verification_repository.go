@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type verificationTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationTokenRepository creates a new instance of VerificationTokenRepository
+func NewVerificationTokenRepository(db *gorm.DB) domain.VerificationTokenRepository {
+	return &verificationTokenRepository{db: db}
+}
+
+func (r *verificationTokenRepository) Create(token *domain.VerificationToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *verificationTokenRepository) GetByHash(purpose, tokenHash string) (*domain.VerificationToken, error) {
+	var token domain.VerificationToken
+	err := r.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token. It reports false if the token was already used.
+func (r *verificationTokenRepository) MarkUsed(id uint64) (bool, error) {
+	result := r.db.Model(&domain.VerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForUser consumes every outstanding token of a user for purpose
+func (r *verificationTokenRepository) InvalidateForUser(userID uint64, purpose string) error {
+	return r.db.Model(&domain.VerificationToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(webhook *domain.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) GetByID(id uint64) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) GetByUserID(userID uint64) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) GetActiveByUserIDs(userIDs []uint64) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.Where("user_id IN ? AND active = ?", userIDs, true).
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(webhook *domain.Webhook) error {
+	return r.db.Save(webhook).Error
+}
+
+func (r *webhookRepository) Delete(id uint64) error {
+	// Start a transaction to delete webhook and its delivery logs
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Webhook{}, id).Error
+	})
+}
+
+// RecordFailure increments the consecutive failure counter and disables the
+// webhook once it reaches maxFailures. It reports whether the webhook was disabled.
+func (r *webhookRepository) RecordFailure(id uint64, maxFailures int) (bool, error) {
+	var webhook domain.Webhook
+	now := time.Now()
+	err := r.db.Raw(`
+		UPDATE webhooks
+		SET consecutive_failures = consecutive_failures + 1,
+			active = CASE WHEN consecutive_failures + 1 >= ? THEN FALSE ELSE active END,
+			disabled_at = CASE WHEN consecutive_failures + 1 >= ? AND active THEN ? ELSE disabled_at END,
+			updated_at = ?
+		WHERE id = ?
+		RETURNING *
+	`, maxFailures, maxFailures, now, now, id).Scan(&webhook).Error
+	if err != nil {
+		return false, err
+	}
+	return !webhook.Active, nil
+}
+
+func (r *webhookRepository) ResetFailures(id uint64) error {
+	return r.db.Model(&domain.Webhook{}).
+		Where("id = ?", id).
+		Update("consecutive_failures", 0).Error
+}
+
+func (r *webhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDeliveryByID(id uint64) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) GetDeliveries(webhookID uint64, page, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	offset := (page - 1) * limit
+
+	err := r.db.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+)
+
+var errInvalidStatusLink = errors.New("invalid or expired status link")
+
+type accountDeletionUsecase struct {
+	deletionRepo     domain.AccountDeletionRepository
+	userRepo         domain.UserRepository
+	postRepo         domain.PostRepository
+	commentRepo      domain.CommentRepository
+	userCache        cache.UserCache
+	postCache        cache.PostCache
+	twoFactorUsecase domain.TwoFactorUsecase
+	mediaStore       storage.Store
+	signingKey       []byte
+	linkBaseURL      string
+	statusLinkTTL    time.Duration
+	leaseTimeout     time.Duration
+	contextTimeout   time.Duration
+}
+
+// NewAccountDeletionUsecase creates a new account deletion usecase. A running
+// deletion not updated for leaseTimeout is picked up by another worker. The
+// deleted user follows the progress for statusLinkTTL through a link built
+// from linkBaseURL and signed with signingKey.
+func NewAccountDeletionUsecase(
+	dr domain.AccountDeletionRepository,
+	ur domain.UserRepository,
+	pr domain.PostRepository,
+	cr domain.CommentRepository,
+	uc cache.UserCache,
+	pc cache.PostCache,
+	tfu domain.TwoFactorUsecase,
+	ms storage.Store,
+	signingKey string,
+	linkBaseURL string,
+	statusLinkTTL time.Duration,
+	leaseTimeout time.Duration,
+	timeout time.Duration,
+) domain.AccountDeletionUsecase {
+	return &accountDeletionUsecase{
+		deletionRepo:     dr,
+		userRepo:         ur,
+		postRepo:         pr,
+		commentRepo:      cr,
+		userCache:        uc,
+		postCache:        pc,
+		twoFactorUsecase: tfu,
+		mediaStore:       ms,
+		signingKey:       []byte(signingKey),
+		linkBaseURL:      linkBaseURL,
+		statusLinkTTL:    statusLinkTTL,
+		leaseTimeout:     leaseTimeout,
+		contextTimeout:   timeout,
+	}
+}
+
+// RequestDeletion closes an account right away and queues the removal of its
+// content and relationships. The user confirms with their password, and a
+// TOTP or recovery code when 2FA is enabled.
+func (a *accountDeletionUsecase) RequestDeletion(userID uint64, password, code string) (*domain.AccountDeletion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if err := confirmIdentity(a.twoFactorUsecase, user, password, code); err != nil {
+		return nil, err
+	}
+
+	// The account can no longer sign in once it is soft deleted, which
+	// happens together with queuing the deletion
+	deletion := &domain.AccountDeletion{
+		UserID: userID,
+		Status: domain.AccountDeletionPending,
+		Step:   domain.AccountDeletionSteps[0],
+	}
+	if err := a.deletionRepo.Create(deletion); err != nil {
+		return nil, err
+	}
+	if err := a.userCache.DeleteUser(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	deletion.StatusURL = a.statusURL(deletion)
+	return deletion, nil
+}
+
+func (a *accountDeletionUsecase) GetDeletion(id uint64) (*domain.AccountDeletion, error) {
+	deletion, err := a.deletionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if deletion == nil {
+		return nil, errors.New("account deletion not found")
+	}
+	return deletion, nil
+}
+
+// GetDeletionStatus checks a signed status link and returns the deletion it
+// points to, the deleted user can no longer authenticate otherwise
+func (a *accountDeletionUsecase) GetDeletionStatus(id uint64, expires int64, signature string) (*domain.AccountDeletion, error) {
+	if !hmac.Equal([]byte(signature), []byte(a.sign(id, expires))) || time.Now().Unix() > expires {
+		return nil, errInvalidStatusLink
+	}
+
+	deletion, err := a.deletionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if deletion == nil {
+		return nil, errInvalidStatusLink
+	}
+	return deletion, nil
+}
+
+// RetryDeletion queues a failed deletion again, resuming at the failed step
+func (a *accountDeletionUsecase) RetryDeletion(id uint64) (*domain.AccountDeletion, error) {
+	deletion, err := a.GetDeletion(id)
+	if err != nil {
+		return nil, err
+	}
+	if deletion.Status != domain.AccountDeletionFailed {
+		return nil, errors.New("only failed account deletions can be retried")
+	}
+
+	deletion.Status = domain.AccountDeletionPending
+	deletion.Error = ""
+	if err := a.deletionRepo.Update(deletion); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// ProcessNext claims a queued deletion and runs its remaining steps, saving
+// the progress after every batch. It reports whether a deletion was claimed.
+func (a *accountDeletionUsecase) ProcessNext(batchSize int) (bool, error) {
+	deletion, err := a.deletionRepo.Claim(time.Now().Add(-a.leaseTimeout))
+	if err != nil {
+		return false, err
+	}
+	if deletion == nil {
+		return false, nil
+	}
+
+	for deletion.Step != domain.AccountDeletionStepDone {
+		stepDone, err := a.runStep(deletion, batchSize)
+		if err != nil {
+			deletion.Status = domain.AccountDeletionFailed
+			deletion.Error = err.Error()
+			if err := a.deletionRepo.Update(deletion); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+			}
+			return true, fmt.Errorf("account deletion %d failed at step %s: %w", deletion.ID, deletion.Step, err)
+		}
+
+		if stepDone {
+			deletion.Step = domain.NextAccountDeletionStep(deletion.Step)
+		}
+		if deletion.Step == domain.AccountDeletionStepDone {
+			now := time.Now()
+			deletion.Status = domain.AccountDeletionCompleted
+			deletion.CompletedAt = &now
+		}
+		if err := a.deletionRepo.Update(deletion); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
+// runStep runs one batch of the current step and reports whether the step is done
+func (a *accountDeletionUsecase) runStep(deletion *domain.AccountDeletion, batchSize int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.contextTimeout)
+	defer cancel()
+
+	switch deletion.Step {
+	case domain.AccountDeletionStepPosts:
+		return a.deletePosts(ctx, deletion, batchSize)
+	case domain.AccountDeletionStepComments:
+		return a.deleteComments(ctx, deletion, batchSize)
+	case domain.AccountDeletionStepLikes:
+		return a.deleteLikes(ctx, deletion, batchSize)
+	case domain.AccountDeletionStepFollows:
+		return a.deleteFollows(ctx, deletion, batchSize)
+	case domain.AccountDeletionStepAccountData:
+		deleted, err := a.deletionRepo.DeleteAccountData(deletion.UserID)
+		if err != nil {
+			return false, err
+		}
+		deletion.RecordsDeleted += deleted
+		return true, nil
+	case domain.AccountDeletionStepUser:
+		return true, a.deleteUser(ctx, deletion.UserID)
+	default:
+		return false, fmt.Errorf("unknown account deletion step %q", deletion.Step)
+	}
+}
+
+// deletePosts purges a batch of the user's posts with their images, and
+// drops the caches showing them, including the feeds of users who shared them
+func (a *accountDeletionUsecase) deletePosts(ctx context.Context, deletion *domain.AccountDeletion, batchSize int) (bool, error) {
+	posts, err := a.deletionRepo.GetPosts(deletion.UserID, batchSize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, post := range posts {
+		shares, err := a.postRepo.GetShares(post.ID)
+		if err != nil {
+			return false, err
+		}
+		mediaURLs, err := a.postRepo.GetMediaURLs(post.ID)
+		if err != nil {
+			return false, err
+		}
+
+		// Deleting first gives the original post its share back
+		if !post.DeletedAt.Valid {
+			if err := a.postRepo.Delete(post.ID); err != nil {
+				return false, err
+			}
+		}
+		if err := a.postRepo.Purge(post.ID); err != nil {
+			return false, err
+		}
+		deletion.PostsDeleted++
+
+		for _, url := range mediaURLs {
+			if err := a.mediaStore.Delete(url); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+			}
+		}
+
+		a.invalidatePost(ctx, post.ID)
+		if post.OriginalPostID != nil {
+			a.invalidatePost(ctx, *post.OriginalPostID)
+		}
+		for _, share := range shares {
+			a.invalidatePost(ctx, share.ID)
+			invalidateFeeds(ctx, a.postCache, a.userRepo, share.UserID)
+		}
+	}
+
+	return len(posts) < batchSize, nil
+}
+
+// deleteComments purges a batch of the user's comments
+func (a *accountDeletionUsecase) deleteComments(ctx context.Context, deletion *domain.AccountDeletion, batchSize int) (bool, error) {
+	comments, err := a.deletionRepo.GetComments(deletion.UserID, batchSize)
+	if err != nil {
+		return false, err
+	}
+
+	postIDs := make(map[uint64]bool)
+	for _, comment := range comments {
+		if err := a.commentRepo.Purge(comment.ID); err != nil {
+			return false, err
+		}
+		deletion.CommentsDeleted++
+		postIDs[comment.PostID] = true
+	}
+	for postID := range postIDs {
+		a.invalidatePost(ctx, postID)
+	}
+
+	return len(comments) < batchSize, nil
+}
+
+// deleteLikes deletes a batch of the user's likes
+func (a *accountDeletionUsecase) deleteLikes(ctx context.Context, deletion *domain.AccountDeletion, batchSize int) (bool, error) {
+	likes, err := a.deletionRepo.DeleteLikes(deletion.UserID, batchSize)
+	if err != nil {
+		return false, err
+	}
+	deletion.LikesDeleted += int64(len(likes))
+
+	postIDs := make(map[uint64]bool)
+	for _, like := range likes {
+		postIDs[like.PostID] = true
+	}
+	for postID := range postIDs {
+		a.invalidatePost(ctx, postID)
+	}
+
+	return len(likes) < batchSize, nil
+}
+
+// deleteFollows deletes a batch of follows from and to the user. Followers
+// get a fresh newsfeed without the user's posts.
+func (a *accountDeletionUsecase) deleteFollows(ctx context.Context, deletion *domain.AccountDeletion, batchSize int) (bool, error) {
+	follows, err := a.deletionRepo.DeleteFollows(deletion.UserID, batchSize)
+	if err != nil {
+		return false, err
+	}
+	deletion.FollowsDeleted += int64(len(follows))
+
+	var followerIDs []uint64
+	for _, follow := range follows {
+		otherID := follow.FollowerID
+		if otherID == deletion.UserID {
+			otherID = follow.FollowingID
+		}
+		if err := a.userCache.DeleteFollows(ctx, otherID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		if follow.FollowingID == deletion.UserID {
+			followerIDs = append(followerIDs, follow.FollowerID)
+		}
+	}
+	if err := a.postCache.DeleteNewsFeeds(ctx, followerIDs...); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return len(follows) < batchSize, nil
+}
+
+// deleteUser purges the user row, the profile images and every cache entry
+// of the user
+func (a *accountDeletionUsecase) deleteUser(ctx context.Context, userID uint64) error {
+	mediaURLs, err := a.userRepo.GetMediaURLs(userID)
+	if err != nil {
+		return err
+	}
+	if err := a.userRepo.Purge(userID); err != nil {
+		return err
+	}
+
+	for _, url := range mediaURLs {
+		if err := a.mediaStore.Delete(url); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	if err := a.userCache.DeleteUser(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+	if err := a.postCache.DeleteUserPosts(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+	if err := a.postCache.DeleteNewsFeeds(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+	return nil
+}
+
+// invalidatePost drops a cached post with its cached comments and likes
+func (a *accountDeletionUsecase) invalidatePost(ctx context.Context, postID uint64) {
+	if err := a.postCache.DeletePost(ctx, postID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+// statusURL returns the signed status link of a deletion
+func (a *accountDeletionUsecase) statusURL(deletion *domain.AccountDeletion) string {
+	expires := deletion.CreatedAt.Add(a.statusLinkTTL).Unix()
+	return fmt.Sprintf("%s/v1/account-deletions/%d/status?expires=%d&signature=%s",
+		a.linkBaseURL, deletion.ID, expires, a.sign(deletion.ID, expires))
+}
+
+// sign returns the hex encoded HMAC-SHA256 of a deletion ID and link expiry.
+// The purpose prefix keeps signatures of other links from passing as these.
+func (a *accountDeletionUsecase) sign(id uint64, expires int64) string {
+	mac := hmac.New(sha256.New, a.signingKey)
+	fmt.Fprintf(mac, "account-deletion:%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
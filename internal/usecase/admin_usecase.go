@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+type adminUsecase struct {
+	userRepo       domain.UserRepository
+	userCache      cache.UserCache
+	postUsecase    domain.PostUsecase
+	commentUsecase domain.CommentUsecase
+	contextTimeout time.Duration
+}
+
+// NewAdminUsecase creates a new admin usecase. Content is deleted through the
+// post and comment usecases so caches and webhooks behave as for owner deletes.
+func NewAdminUsecase(ur domain.UserRepository, uc cache.UserCache, pu domain.PostUsecase, cu domain.CommentUsecase, timeout time.Duration) domain.AdminUsecase {
+	return &adminUsecase{
+		userRepo:       ur,
+		userCache:      uc,
+		postUsecase:    pu,
+		commentUsecase: cu,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *adminUsecase) ListUsers(page, limit int) ([]domain.User, error) {
+	return a.userRepo.List(page, limit)
+}
+
+func (a *adminUsecase) SuspendUser(actorID, userID uint64, reason string) (*domain.User, error) {
+	user, err := a.getManagedUser(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Suspending again only updates the reason
+	if !user.IsSuspended() {
+		now := time.Now()
+		user.SuspendedAt = &now
+	}
+	user.SuspensionReason = reason
+
+	if err := a.updateUser(user, "suspended_at", "suspension_reason"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (a *adminUsecase) UnsuspendUser(actorID, userID uint64) (*domain.User, error) {
+	user, err := a.getManagedUser(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.SuspendedAt = nil
+	user.SuspensionReason = ""
+
+	if err := a.updateUser(user, "suspended_at", "suspension_reason"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetRole changes the role of a user. Nobody can grant a role above their own.
+func (a *adminUsecase) SetRole(actorID, userID uint64, role string) (*domain.User, error) {
+	if !domain.IsValidRole(role) {
+		return nil, domain.ErrInvalidRole
+	}
+
+	actor, err := a.getUser(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Outranks(role, actor.Role) {
+		return nil, fmt.Errorf("%w: cannot grant a role above your own", domain.ErrForbidden)
+	}
+
+	user, err := a.getManagedUser(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+
+	if err := a.updateUser(user, "role"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeletePost deletes any post, including posts hidden by moderation. Its
+// author cannot restore it.
+func (a *adminUsecase) DeletePost(postID uint64) error {
+	return a.postUsecase.RemovePost(postID)
+}
+
+func (a *adminUsecase) DeleteComment(commentID uint64) error {
+	// Verify comment exists
+	if _, err := a.commentUsecase.GetComment(commentID); err != nil {
+		return err
+	}
+
+	return a.commentUsecase.DeleteComment(commentID)
+}
+
+// getManagedUser returns a user the actor may manage, which requires a role
+// strictly above the user's own
+func (a *adminUsecase) getManagedUser(actorID, userID uint64) (*domain.User, error) {
+	if actorID == userID {
+		return nil, fmt.Errorf("%w: cannot manage your own account", domain.ErrForbidden)
+	}
+
+	actor, err := a.getUser(actorID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.Outranks(actor.Role, user.Role) {
+		return nil, fmt.Errorf("%w: role does not outrank the user", domain.ErrForbidden)
+	}
+
+	return user, nil
+}
+
+func (a *adminUsecase) getUser(userID uint64) (*domain.User, error) {
+	user, err := a.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (a *adminUsecase) updateUser(user *domain.User, columns ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.contextTimeout)
+	defer cancel()
+
+	user.UpdatedAt = time.Now()
+	if err := a.userRepo.Update(user, columns...); err != nil {
+		return err
+	}
+
+	// Invalidate user cache so the auth middleware sees the change
+	if err := a.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
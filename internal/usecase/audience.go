@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+)
+
+// Audiences group the viewers of an author's posts by what they can see
+const (
+	audienceSelf         = "self"
+	audienceCloseFriends = "close_friends"
+	audienceFollowers    = "followers"
+	audiencePublic       = "public"
+)
+
+// audienceVisibilities lists the post visibilities each audience can see
+var audienceVisibilities = map[string][]string{
+	audienceSelf: {
+		domain.PostVisibilityPublic,
+		domain.PostVisibilityFollowers,
+		domain.PostVisibilityCloseFriends,
+		domain.PostVisibilityOnlyMe,
+	},
+	audienceCloseFriends: {
+		domain.PostVisibilityPublic,
+		domain.PostVisibilityFollowers,
+		domain.PostVisibilityCloseFriends,
+	},
+	audienceFollowers: {
+		domain.PostVisibilityPublic,
+		domain.PostVisibilityFollowers,
+	},
+	audiencePublic: {
+		domain.PostVisibilityPublic,
+	},
+}
+
+// AudienceResolver decides which posts of an author a viewer may see. Blocked
+// users see none. Close friends see close friends posts even without
+// following. Everyone else needs to follow a private account to see any of its posts.
+type AudienceResolver struct {
+	userRepo        domain.UserRepository
+	closeFriendRepo domain.CloseFriendRepository
+	blockRepo       domain.BlockRepository
+}
+
+// NewAudienceResolver creates a new audience resolver
+func NewAudienceResolver(ur domain.UserRepository, cfr domain.CloseFriendRepository, br domain.BlockRepository) *AudienceResolver {
+	return &AudienceResolver{
+		userRepo:        ur,
+		closeFriendRepo: cfr,
+		blockRepo:       br,
+	}
+}
+
+// Resolve returns the audience viewerID belongs to for the posts of authorID
+func (a *AudienceResolver) Resolve(viewerID, authorID uint64) (string, error) {
+	if viewerID == authorID {
+		return audienceSelf, nil
+	}
+
+	author, err := a.userRepo.GetByID(authorID)
+	if err != nil {
+		return "", err
+	}
+	if author == nil {
+		return "", errors.New("user not found")
+	}
+
+	blocked, err := a.blockRepo.IsBlocked(viewerID, authorID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", errBlocked
+	}
+
+	closeFriend, err := a.closeFriendRepo.IsCloseFriend(authorID, viewerID)
+	if err != nil {
+		return "", err
+	}
+	if closeFriend {
+		return audienceCloseFriends, nil
+	}
+
+	following, err := a.userRepo.IsFollowing(viewerID, authorID)
+	if err != nil {
+		return "", err
+	}
+	if following {
+		return audienceFollowers, nil
+	}
+	if author.IsPrivate {
+		return "", errPrivateAccount
+	}
+	return audiencePublic, nil
+}
+
+// CheckPost returns an error unless viewerID may see post. Drafts and
+// scheduled posts are not seen as posts until they are published, and posts
+// hidden by moderation are only reachable through the moderation queue.
+func (a *AudienceResolver) CheckPost(viewerID uint64, post *domain.Post) error {
+	if post.IsPending() || post.HiddenAt != nil {
+		return errors.New("post not found")
+	}
+
+	audience, err := a.Resolve(viewerID, post.UserID)
+	if err != nil {
+		return err
+	}
+	if !canSeeVisibility(audience, post.Visibility) {
+		return errors.New("post not found")
+	}
+	return nil
+}
+
+// FilterBlocked hides what users blocked with viewerID left on posts: the
+// originals of reposts and quotes, and the embedded likes and comments. Quotes
+// are kept without their original, reposts are left out. Shared caches hold
+// posts for every viewer, so this runs after them.
+func (a *AudienceResolver) FilterBlocked(viewerID uint64, posts []domain.Post) ([]domain.Post, error) {
+	hasOthers := false
+	for _, post := range posts {
+		if post.OriginalPost != nil || len(post.Likes) > 0 || len(post.Comments) > 0 {
+			hasOthers = true
+			break
+		}
+	}
+	if !hasOthers {
+		return posts, nil
+	}
+
+	blockedIDs, err := a.blockRepo.GetBlockedIDs(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockedIDs) == 0 {
+		return posts, nil
+	}
+	blocked := make(map[uint64]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+
+	filtered := make([]domain.Post, 0, len(posts))
+	for _, post := range posts {
+		if post.OriginalPost != nil && blocked[post.OriginalPost.UserID] {
+			if post.IsRepost() {
+				continue
+			}
+			post.OriginalPost = nil
+		}
+
+		// Copy into new slices, the posts may share them with a cached page
+		likes := make([]domain.Like, 0, len(post.Likes))
+		for _, like := range post.Likes {
+			if !blocked[like.UserID] {
+				likes = append(likes, like)
+			}
+		}
+		post.Likes = likes
+		comments := make([]domain.Comment, 0, len(post.Comments))
+		for _, comment := range post.Comments {
+			if !blocked[comment.UserID] {
+				comments = append(comments, comment)
+			}
+		}
+		post.Comments = comments
+
+		filtered = append(filtered, post)
+	}
+	return filtered, nil
+}
+
+func canSeeVisibility(audience, visibility string) bool {
+	for _, v := range audienceVisibilities[audience] {
+		if v == visibility {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidPostVisibility(visibility string) bool {
+	switch visibility {
+	case domain.PostVisibilityPublic,
+		domain.PostVisibilityFollowers,
+		domain.PostVisibilityCloseFriends,
+		domain.PostVisibilityOnlyMe:
+		return true
+	}
+	return false
+}
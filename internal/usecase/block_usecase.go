@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+// errBlocked is returned when one of two users has blocked the other
+var errBlocked = errors.New("you cannot interact with this user")
+
+type blockUsecase struct {
+	blockRepo      domain.BlockRepository
+	userRepo       domain.UserRepository
+	userCache      cache.UserCache
+	postCache      cache.PostCache
+	contextTimeout time.Duration
+}
+
+// NewBlockUsecase creates a new block usecase
+func NewBlockUsecase(br domain.BlockRepository, ur domain.UserRepository, uc cache.UserCache, pc cache.PostCache, timeout time.Duration) domain.BlockUsecase {
+	return &blockUsecase{
+		blockRepo:      br,
+		userRepo:       ur,
+		userCache:      uc,
+		postCache:      pc,
+		contextTimeout: timeout,
+	}
+}
+
+// Block blocks a user and removes the follows between both users
+func (b *blockUsecase) Block(blockerID, blockedID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	if err := b.checkTarget(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	block := &domain.Block{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: time.Now(),
+	}
+	if err := b.blockRepo.Block(block); err != nil {
+		return err
+	}
+
+	// The follows are gone, so both users' follow counts and feeds are stale
+	for _, userID := range []uint64{blockerID, blockedID} {
+		if err := b.userCache.DeleteFollows(ctx, userID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		b.invalidateNewsFeed(ctx, userID)
+	}
+
+	return nil
+}
+
+func (b *blockUsecase) Unblock(blockerID, blockedID uint64) error {
+	return b.blockRepo.Unblock(blockerID, blockedID)
+}
+
+func (b *blockUsecase) GetBlocked(userID uint64, page, limit int) ([]domain.User, error) {
+	return b.blockRepo.GetBlocked(userID, page, limit)
+}
+
+// Mute hides a user's posts from the muter's newsfeed
+func (b *blockUsecase) Mute(muterID, mutedID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	if err := b.checkTarget(muterID, mutedID); err != nil {
+		return err
+	}
+
+	mute := &domain.Mute{
+		MuterID:   muterID,
+		MutedID:   mutedID,
+		CreatedAt: time.Now(),
+	}
+	if err := b.blockRepo.Mute(mute); err != nil {
+		return err
+	}
+
+	b.invalidateNewsFeed(ctx, muterID)
+
+	return nil
+}
+
+func (b *blockUsecase) Unmute(muterID, mutedID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	if err := b.blockRepo.Unmute(muterID, mutedID); err != nil {
+		return err
+	}
+
+	b.invalidateNewsFeed(ctx, muterID)
+
+	return nil
+}
+
+func (b *blockUsecase) GetMuted(userID uint64, page, limit int) ([]domain.User, error) {
+	return b.blockRepo.GetMuted(userID, page, limit)
+}
+
+// checkTarget verifies that a user can block or mute the target
+func (b *blockUsecase) checkTarget(userID, targetID uint64) error {
+	if userID == targetID {
+		return errors.New("cannot block or mute yourself")
+	}
+
+	target, err := b.userRepo.GetByID(targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (b *blockUsecase) invalidateNewsFeed(ctx context.Context, userID uint64) {
+	if err := b.postCache.DeleteNewsFeeds(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+type bookmarkUsecase struct {
+	bookmarkRepo   domain.BookmarkRepository
+	bookmarkCache  cache.BookmarkCache
+	postRepo       domain.PostRepository
+	audience       *AudienceResolver
+	contextTimeout time.Duration
+}
+
+// NewBookmarkUsecase creates a new bookmark usecase
+func NewBookmarkUsecase(
+	br domain.BookmarkRepository,
+	bc cache.BookmarkCache,
+	pr domain.PostRepository,
+	ar *AudienceResolver,
+	timeout time.Duration,
+) domain.BookmarkUsecase {
+	return &bookmarkUsecase{
+		bookmarkRepo:   br,
+		bookmarkCache:  bc,
+		postRepo:       pr,
+		audience:       ar,
+		contextTimeout: timeout,
+	}
+}
+
+// Bookmark saves a post the user can see. Bookmarking an already saved post
+// moves it to the given collection.
+func (b *bookmarkUsecase) Bookmark(userID, postID uint64, collectionID *uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	post, err := b.postRepo.GetByID(postID)
+	if err != nil {
+		return err
+	}
+	if post == nil || post.HiddenAt != nil {
+		return errors.New("post not found")
+	}
+	if err := b.audience.CheckPost(userID, post); err != nil {
+		return err
+	}
+
+	if collectionID != nil {
+		if _, err := b.getOwnCollection(userID, *collectionID); err != nil {
+			return err
+		}
+	}
+
+	bookmark := &domain.Bookmark{
+		UserID:       userID,
+		PostID:       postID,
+		CollectionID: collectionID,
+		CreatedAt:    time.Now(),
+	}
+	if err := b.bookmarkRepo.Upsert(bookmark); err != nil {
+		return err
+	}
+
+	b.invalidateCache(ctx, userID)
+
+	return nil
+}
+
+func (b *bookmarkUsecase) RemoveBookmark(userID, postID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	if err := b.bookmarkRepo.Delete(userID, postID); err != nil {
+		return err
+	}
+
+	b.invalidateCache(ctx, userID)
+
+	return nil
+}
+
+// GetBookmarks returns the user's bookmarks newest first. Posts the user can no
+// longer see are left out, so a page may hold fewer than limit bookmarks even
+// when more follow.
+func (b *bookmarkUsecase) GetBookmarks(userID uint64, collectionID *uint64, cursor uint64, limit int) (*domain.BookmarkPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	// Try to get from cache first
+	page, err := b.bookmarkCache.GetBookmarks(ctx, userID, collectionID, cursor, limit)
+	if err == nil && page != nil {
+		return page, nil
+	}
+
+	if collectionID != nil {
+		if _, err := b.getOwnCollection(userID, *collectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	bookmarks, err := b.bookmarkRepo.List(userID, collectionID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page = &domain.BookmarkPage{Bookmarks: []domain.Bookmark{}}
+	if len(bookmarks) == limit {
+		page.NextCursor = bookmarks[len(bookmarks)-1].ID
+	}
+	for _, bookmark := range bookmarks {
+		post := bookmark.Post
+		if post == nil || (post.IsRepost() && post.OriginalPost == nil) {
+			continue
+		}
+		if err := b.audience.CheckPost(userID, post); err != nil {
+			continue
+		}
+		page.Bookmarks = append(page.Bookmarks, bookmark)
+	}
+
+	// Cache bookmarks
+	if err := b.bookmarkCache.SetBookmarks(ctx, userID, collectionID, cursor, limit, page); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return page, nil
+}
+
+// GetBookmarkedPostIDs returns the set of posts the user has bookmarked
+func (b *bookmarkUsecase) GetBookmarkedPostIDs(userID uint64) (map[uint64]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	// Try to get from cache first
+	postIDs, err := b.bookmarkCache.GetBookmarkedPostIDs(ctx, userID)
+	if err != nil || postIDs == nil {
+		postIDs, err = b.bookmarkRepo.GetBookmarkedPostIDs(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Cache bookmarked post IDs
+		if err := b.bookmarkCache.SetBookmarkedPostIDs(ctx, userID, postIDs); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	bookmarked := make(map[uint64]bool, len(postIDs))
+	for _, id := range postIDs {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
+
+func (b *bookmarkUsecase) CreateCollection(userID uint64, name string) (*domain.BookmarkCollection, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("collection name is required")
+	}
+
+	collections, err := b.bookmarkRepo.ListCollections(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, collection := range collections {
+		if collection.Name == name {
+			return nil, errors.New("collection already exists")
+		}
+	}
+
+	collection := &domain.BookmarkCollection{
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := b.bookmarkRepo.CreateCollection(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (b *bookmarkUsecase) GetCollections(userID uint64) ([]domain.BookmarkCollection, error) {
+	return b.bookmarkRepo.ListCollections(userID)
+}
+
+// DeleteCollection deletes a collection but keeps its bookmarks
+func (b *bookmarkUsecase) DeleteCollection(userID, collectionID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.contextTimeout)
+	defer cancel()
+
+	if _, err := b.getOwnCollection(userID, collectionID); err != nil {
+		return err
+	}
+
+	if err := b.bookmarkRepo.DeleteCollection(collectionID); err != nil {
+		return err
+	}
+
+	b.invalidateCache(ctx, userID)
+
+	return nil
+}
+
+// getOwnCollection loads a bookmark collection owned by userID
+func (b *bookmarkUsecase) getOwnCollection(userID, collectionID uint64) (*domain.BookmarkCollection, error) {
+	collection, err := b.bookmarkRepo.GetCollection(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil || collection.UserID != userID {
+		return nil, errors.New("collection not found")
+	}
+	return collection, nil
+}
+
+func (b *bookmarkUsecase) invalidateCache(ctx context.Context, userID uint64) {
+	if err := b.bookmarkCache.DeleteBookmarks(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
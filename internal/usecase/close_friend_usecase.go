@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+type closeFriendUsecase struct {
+	closeFriendRepo domain.CloseFriendRepository
+	userRepo        domain.UserRepository
+	blockRepo       domain.BlockRepository
+	postCache       cache.PostCache
+	contextTimeout  time.Duration
+}
+
+// NewCloseFriendUsecase creates a new close friend usecase
+func NewCloseFriendUsecase(
+	cfr domain.CloseFriendRepository,
+	ur domain.UserRepository,
+	br domain.BlockRepository,
+	pc cache.PostCache,
+	timeout time.Duration,
+) domain.CloseFriendUsecase {
+	return &closeFriendUsecase{
+		closeFriendRepo: cfr,
+		userRepo:        ur,
+		blockRepo:       br,
+		postCache:       pc,
+		contextTimeout:  timeout,
+	}
+}
+
+func (f *closeFriendUsecase) AddCloseFriend(userID, friendID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.contextTimeout)
+	defer cancel()
+
+	if userID == friendID {
+		return errors.New("cannot add yourself as a close friend")
+	}
+
+	friend, err := f.userRepo.GetByID(friendID)
+	if err != nil {
+		return err
+	}
+	if friend == nil {
+		return errors.New("user not found")
+	}
+
+	blocked, err := f.blockRepo.IsBlocked(userID, friendID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errBlocked
+	}
+
+	closeFriend := &domain.CloseFriend{
+		UserID:    userID,
+		FriendID:  friendID,
+		CreatedAt: time.Now(),
+	}
+	if err := f.closeFriendRepo.Add(closeFriend); err != nil {
+		return err
+	}
+
+	f.invalidateNewsFeed(ctx, friendID)
+
+	return nil
+}
+
+func (f *closeFriendUsecase) RemoveCloseFriend(userID, friendID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.contextTimeout)
+	defer cancel()
+
+	if err := f.closeFriendRepo.Remove(userID, friendID); err != nil {
+		return err
+	}
+
+	f.invalidateNewsFeed(ctx, friendID)
+
+	return nil
+}
+
+func (f *closeFriendUsecase) GetCloseFriends(userID uint64, page, limit int) ([]domain.User, error) {
+	return f.closeFriendRepo.List(userID, page, limit)
+}
+
+// invalidateNewsFeed drops the cached newsfeed of a user whose access to close
+// friends posts changed
+func (f *closeFriendUsecase) invalidateNewsFeed(ctx context.Context, userID uint64) {
+	if err := f.postCache.DeleteNewsFeeds(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
@@ -14,6 +14,10 @@ type commentUsecase struct {
 	commentCache cache.CommentCache
 	postRepo    domain.PostRepository
 	userRepo    domain.UserRepository
+	webhooks    domain.WebhookDispatcher
+	contentFilter domain.ContentFilterUsecase
+	blockRepo   domain.BlockRepository
+	audience    *AudienceResolver
 	contextTimeout time.Duration
 }
 
@@ -23,6 +27,10 @@ func NewCommentUsecase(
 	cc cache.CommentCache,
 	pr domain.PostRepository,
 	ur domain.UserRepository,
+	wd domain.WebhookDispatcher,
+	cf domain.ContentFilterUsecase,
+	br domain.BlockRepository,
+	ar *AudienceResolver,
 	timeout time.Duration,
 ) domain.CommentUsecase {
 	return &commentUsecase{
@@ -30,6 +38,10 @@ func NewCommentUsecase(
 		commentCache: cc,
 		postRepo:    pr,
 		userRepo:    ur,
+		webhooks:    wd,
+		contentFilter: cf,
+		blockRepo:   br,
+		audience:    ar,
 		contextTimeout: timeout,
 	}
 }
@@ -56,6 +68,26 @@ func (c *commentUsecase) CreateComment(comment *domain.Comment) error {
 		return errors.New("post not found")
 	}
 
+	// Blocked users cannot comment on each other's posts
+	blocked, err := c.blockRepo.IsBlocked(comment.UserID, post.UserID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errBlocked
+	}
+
+	// Only viewers of a post can comment on it
+	if err := c.audience.CheckPost(comment.UserID, post); err != nil {
+		return err
+	}
+
+	// Run content filters, rejected comments are not written
+	screening, err := c.contentFilter.Screen(comment.UserID, domain.ReportTargetComment, comment.Content)
+	if err != nil {
+		return err
+	}
+
 	// Set timestamps
 	now := time.Now()
 	comment.CreatedAt = now
@@ -65,6 +97,7 @@ func (c *commentUsecase) CreateComment(comment *domain.Comment) error {
 	if err := c.commentRepo.Create(comment); err != nil {
 		return err
 	}
+	c.contentFilter.Attach(screening, comment.ID)
 
 	// Invalidate post comments cache
 	if err := c.commentCache.DeletePostComments(ctx, comment.PostID); err != nil {
@@ -72,32 +105,55 @@ func (c *commentUsecase) CreateComment(comment *domain.Comment) error {
 		// TODO: Add proper logging
 	}
 
+	c.webhooks.Dispatch(domain.EventCommentCreated, comment, post.UserID, comment.UserID)
+
 	return nil
 }
 
 func (c *commentUsecase) GetComment(id uint64) (*domain.Comment, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.contextTimeout)
-	defer cancel()
-
 	comment, err := c.commentRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if comment == nil {
-		return nil, errors.New("comment not found")
+		return nil, domain.ErrCommentNotFound
 	}
 
 	return comment, nil
 }
 
-func (c *commentUsecase) GetPostComments(postID uint64, page, limit int) ([]domain.Comment, error) {
+// GetPostComments returns a page of comments as seen by viewerID. Comments of
+// users blocked by or blocking the viewer are left out after the shared cache,
+// which is only read once the viewer may see the post.
+func (c *commentUsecase) GetPostComments(postID, viewerID uint64, page, limit int) ([]domain.Comment, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.contextTimeout)
 	defer cancel()
 
+	post, err := c.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.HiddenAt != nil {
+		return nil, errors.New("post not found")
+	}
+	if err := c.audience.CheckPost(viewerID, post); err != nil {
+		return nil, err
+	}
+
+	blockedIDs, err := c.blockRepo.GetBlockedIDs(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range blockedIDs {
+		if id == post.UserID {
+			return nil, errors.New("post not found")
+		}
+	}
+
 	// Try to get from cache first
 	comments, err := c.commentCache.GetPostComments(ctx, postID, page)
 	if err == nil {
-		return comments, nil
+		return excludeBlocked(comments, blockedIDs), nil
 	}
 
 	// If not in cache, get from database
@@ -112,7 +168,7 @@ func (c *commentUsecase) GetPostComments(postID uint64, page, limit int) ([]doma
 		// TODO: Add proper logging
 	}
 
-	return comments, nil
+	return excludeBlocked(comments, blockedIDs), nil
 }
 
 func (c *commentUsecase) UpdateComment(comment *domain.Comment) error {
@@ -124,20 +180,35 @@ func (c *commentUsecase) UpdateComment(comment *domain.Comment) error {
 	if err != nil {
 		return err
 	}
-	if existingComment == nil {
+	if existingComment == nil || existingComment.PostID != comment.PostID {
 		return errors.New("comment not found")
 	}
 	if existingComment.UserID != comment.UserID {
 		return errors.New("unauthorized")
 	}
 
+	// Edits go through the content filters like new comments. Unchanged
+	// content was screened already and would only match itself as a duplicate.
+	var screening *domain.ContentScreening
+	if comment.Content != existingComment.Content {
+		screening, err = c.contentFilter.Screen(comment.UserID, domain.ReportTargetComment, comment.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only the content changes, moderation state and timestamps are kept
+	existingComment.Content = comment.Content
+
 	// Update timestamp
-	comment.UpdatedAt = time.Now()
+	existingComment.UpdatedAt = time.Now()
 
 	// Update in database
-	if err := c.commentRepo.Update(comment); err != nil {
+	if err := c.commentRepo.Update(existingComment); err != nil {
 		return err
 	}
+	c.contentFilter.Attach(screening, existingComment.ID)
+	*comment = *existingComment
 
 	// Invalidate post comments cache
 	if err := c.commentCache.DeletePostComments(ctx, comment.PostID); err != nil {
@@ -145,6 +216,8 @@ func (c *commentUsecase) UpdateComment(comment *domain.Comment) error {
 		// TODO: Add proper logging
 	}
 
+	c.webhooks.Dispatch(domain.EventCommentUpdated, comment, c.postOwnerID(comment.PostID), comment.UserID)
+
 	return nil
 }
 
@@ -158,7 +231,7 @@ func (c *commentUsecase) DeleteComment(id uint64) error {
 		return err
 	}
 	if comment == nil {
-		return errors.New("comment not found")
+		return domain.ErrCommentNotFound
 	}
 
 	// Delete from database
@@ -172,5 +245,99 @@ func (c *commentUsecase) DeleteComment(id uint64) error {
 		// TODO: Add proper logging
 	}
 
+	c.webhooks.Dispatch(domain.EventCommentDeleted, comment, c.postOwnerID(comment.PostID), comment.UserID)
+
 	return nil
 }
+
+// SetCommentHidden hides a comment from its post or restores it
+func (c *commentUsecase) SetCommentHidden(id uint64, hidden bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.contextTimeout)
+	defer cancel()
+
+	comment, err := c.commentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if comment == nil {
+		return errors.New("comment not found")
+	}
+
+	var hiddenAt *time.Time
+	if hidden {
+		now := time.Now()
+		hiddenAt = &now
+	}
+	if err := c.commentRepo.SetHidden(id, hiddenAt); err != nil {
+		return err
+	}
+
+	// Invalidate post comments cache
+	if err := c.commentCache.DeletePostComments(ctx, comment.PostID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// GetCommentRevisions returns the previous versions of a comment to a viewer
+// who may see it
+func (c *commentUsecase) GetCommentRevisions(postID, commentID, viewerID uint64, page, limit int) ([]domain.CommentRevision, error) {
+	post, err := c.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.HiddenAt != nil {
+		return nil, errors.New("post not found")
+	}
+	if err := c.audience.CheckPost(viewerID, post); err != nil {
+		return nil, err
+	}
+
+	comment, err := c.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.PostID != postID || comment.HiddenAt != nil {
+		return nil, errors.New("comment not found")
+	}
+	blocked, err := c.blockRepo.IsBlocked(viewerID, comment.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("comment not found")
+	}
+
+	return c.commentRepo.GetRevisions(commentID, page, limit)
+}
+
+// postOwnerID returns the author of a post, or 0 if it cannot be found
+func (c *commentUsecase) postOwnerID(postID uint64) uint64 {
+	post, err := c.postRepo.GetByID(postID)
+	if err != nil || post == nil {
+		return 0
+	}
+	return post.UserID
+}
+
+// excludeBlocked drops the comments written by users in blockedIDs
+func excludeBlocked(comments []domain.Comment, blockedIDs []uint64) []domain.Comment {
+	if len(blockedIDs) == 0 {
+		return comments
+	}
+
+	blocked := make(map[uint64]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+
+	visible := make([]domain.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if !blocked[comment.UserID] {
+			visible = append(visible, comment)
+		}
+	}
+	return visible
+}
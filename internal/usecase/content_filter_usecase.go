@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/contentfilter"
+)
+
+type contentFilterUsecase struct {
+	decisionRepo   domain.FilterDecisionRepository
+	chain          *contentfilter.Chain
+	contextTimeout time.Duration
+}
+
+// NewContentFilterUsecase creates a new content filter usecase running chain
+// on new posts and comments
+func NewContentFilterUsecase(dr domain.FilterDecisionRepository, chain *contentfilter.Chain, timeout time.Duration) domain.ContentFilterUsecase {
+	return &contentFilterUsecase{
+		decisionRepo:   dr,
+		chain:          chain,
+		contextTimeout: timeout,
+	}
+}
+
+// Screen runs content through the filters and records every flag or
+// rejection. Rejected content returns an error carrying the reason.
+func (f *contentFilterUsecase) Screen(userID uint64, targetType, content string) (*domain.ContentScreening, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.contextTimeout)
+	defer cancel()
+
+	decision, results, errs := f.chain.Run(ctx, &contentfilter.Submission{
+		UserID:  userID,
+		Kind:    targetType,
+		Content: content,
+	})
+	if len(errs) > 0 {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	screening := &domain.ContentScreening{Decision: string(decision)}
+	if len(results) == 0 {
+		return screening, nil
+	}
+	screening.Reason = results[len(results)-1].Reason
+
+	now := time.Now()
+	decisions := make([]domain.FilterDecision, len(results))
+	for i, result := range results {
+		decisions[i] = domain.FilterDecision{
+			UserID:     userID,
+			TargetType: targetType,
+			Filter:     result.Filter,
+			Decision:   string(result.Decision),
+			Reason:     result.Reason,
+			Content:    content,
+			CreatedAt:  now,
+		}
+	}
+	if err := f.decisionRepo.Create(decisions); err != nil {
+		return nil, err
+	}
+	for _, d := range decisions {
+		screening.DecisionIDs = append(screening.DecisionIDs, d.ID)
+	}
+
+	if decision == contentfilter.Reject {
+		return screening, errors.New("content rejected: " + screening.Reason)
+	}
+	return screening, nil
+}
+
+// Attach links the recorded decisions of flagged content to the written post or comment
+func (f *contentFilterUsecase) Attach(screening *domain.ContentScreening, targetID uint64) {
+	if screening == nil || len(screening.DecisionIDs) == 0 {
+		return
+	}
+	if err := f.decisionRepo.AttachTarget(screening.DecisionIDs, targetID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+func (f *contentFilterUsecase) ListDecisions(decision string, page, limit int) ([]domain.FilterDecision, error) {
+	return f.decisionRepo.List(decision, page, limit)
+}
+
+type duplicateCounter struct {
+	postRepo    domain.PostRepository
+	commentRepo domain.CommentRepository
+}
+
+// NewDuplicateCounter counts recent identical posts and comments for the duplicate filter
+func NewDuplicateCounter(pr domain.PostRepository, cr domain.CommentRepository) contentfilter.DuplicateCounter {
+	return &duplicateCounter{postRepo: pr, commentRepo: cr}
+}
+
+func (d *duplicateCounter) CountRecentDuplicates(userID uint64, kind, content string, since time.Time) (int64, error) {
+	if kind == domain.ReportTargetComment {
+		return d.commentRepo.CountRecentByContent(userID, content, since)
+	}
+	return d.postRepo.CountRecentByContent(userID, content, since)
+}
@@ -0,0 +1,481 @@
+package usecase
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/mailer"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+)
+
+var errInvalidDownloadLink = errors.New("invalid or expired download link")
+
+type dataExportUsecase struct {
+	exportRepo   domain.DataExportRepository
+	userRepo     domain.UserRepository
+	mediaStore   storage.Store
+	exportStore  storage.Store
+	mailer       mailer.Mailer
+	signingKey   []byte
+	linkBaseURL  string
+	linkTTL      time.Duration
+	leaseTimeout time.Duration
+}
+
+// NewDataExportUsecase creates a new data export usecase. Archives are kept
+// in exportStore and can be downloaded for linkTTL through links built from
+// linkBaseURL and signed with signingKey.
+func NewDataExportUsecase(
+	er domain.DataExportRepository,
+	ur domain.UserRepository,
+	ms storage.Store,
+	es storage.Store,
+	m mailer.Mailer,
+	signingKey string,
+	linkBaseURL string,
+	linkTTL time.Duration,
+	leaseTimeout time.Duration,
+) domain.DataExportUsecase {
+	return &dataExportUsecase{
+		exportRepo:   er,
+		userRepo:     ur,
+		mediaStore:   ms,
+		exportStore:  es,
+		mailer:       m,
+		signingKey:   []byte(signingKey),
+		linkBaseURL:  linkBaseURL,
+		linkTTL:      linkTTL,
+		leaseTimeout: leaseTimeout,
+	}
+}
+
+// RequestExport queues an export of a user's data. A user has at most one
+// export in progress, requesting another one returns it.
+func (d *dataExportUsecase) RequestExport(userID uint64, includeHTML bool) (*domain.DataExport, error) {
+	user, err := d.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	active, err := d.exportRepo.GetActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	export := &domain.DataExport{
+		UserID:      userID,
+		Status:      domain.DataExportPending,
+		IncludeHTML: includeHTML,
+	}
+	if err := d.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetExport returns an export of userID, with its download link once completed
+func (d *dataExportUsecase) GetExport(id, userID uint64) (*domain.DataExport, error) {
+	export, err := d.exportRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, errors.New("data export not found")
+	}
+
+	if export.Status == domain.DataExportCompleted {
+		export.DownloadURL = d.downloadURL(export)
+	}
+	return export, nil
+}
+
+// OpenDownload checks a signed download link and opens the archive it points to
+func (d *dataExportUsecase) OpenDownload(id uint64, expires int64, signature string) (*domain.DataExport, io.ReadCloser, error) {
+	if !hmac.Equal([]byte(signature), []byte(d.sign(id, expires))) || time.Now().Unix() > expires {
+		return nil, nil, errInvalidDownloadLink
+	}
+
+	export, err := d.exportRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export == nil || export.Status != domain.DataExportCompleted || time.Now().After(*export.ExpiresAt) {
+		return nil, nil, errInvalidDownloadLink
+	}
+
+	file, err := d.exportStore.Open(export.FileURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, file, nil
+}
+
+// ProcessNext claims a queued export, builds its archive and emails the
+// download link. It reports whether an export was claimed.
+func (d *dataExportUsecase) ProcessNext(batchSize int) (bool, error) {
+	export, err := d.exportRepo.Claim(time.Now().Add(-d.leaseTimeout))
+	if err != nil {
+		return false, err
+	}
+	if export == nil {
+		return false, nil
+	}
+
+	user, err := d.userRepo.GetByID(export.UserID)
+	if err == nil && user == nil {
+		err = errors.New("user not found")
+	}
+	if err == nil {
+		err = d.build(export, user, batchSize)
+	}
+	if err != nil {
+		export.Status = domain.DataExportFailed
+		export.Error = err.Error()
+		if err := d.exportRepo.Update(export); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		return true, fmt.Errorf("data export %d failed: %w", export.ID, err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(d.linkTTL)
+	export.Status = domain.DataExportCompleted
+	export.Error = ""
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := d.exportRepo.Update(export); err != nil {
+		return true, err
+	}
+
+	err = d.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe copy of your data you asked for is ready. Download it from the link below:\n\n%s\n\nThis link expires in %s.\n",
+			user.FirstName, d.downloadURL(export), d.linkTTL,
+		),
+	})
+	if err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return true, nil
+}
+
+// DeleteExpired deletes up to limit exports whose download expired, with
+// their archives, and returns how many were deleted
+func (d *dataExportUsecase) DeleteExpired(limit int) (int, error) {
+	exports, err := d.exportRepo.GetExpired(time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, export := range exports {
+		if err := d.exportStore.Delete(export.FileURL); err != nil {
+			return deleted, err
+		}
+		if err := d.exportRepo.Delete(export.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// exportedUser is a follower or followee as listed in an export, without
+// their private details
+type exportedUser struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// exportData is the content of an export archive
+type exportData struct {
+	Profile   *domain.User
+	Posts     []domain.Post
+	Comments  []domain.Comment
+	Likes     []domain.Like
+	Followers []exportedUser
+	Following []exportedUser
+	// Media maps post IDs to the archive path of their image
+	Media map[uint64]string
+}
+
+// build collects the data of user batch by batch and stores the archive
+func (d *dataExportUsecase) build(export *domain.DataExport, user *domain.User, batchSize int) error {
+	data, err := d.collect(user, batchSize)
+	if err != nil {
+		return err
+	}
+
+	// Stream the archive into the store instead of holding it in memory
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	counter := &countingWriter{w: pw}
+	go func() {
+		pw.CloseWithError(d.writeArchive(counter, data, export.IncludeHTML))
+	}()
+
+	fileURL, err := d.exportStore.Put(fmt.Sprintf("export-%d.zip", export.ID), pr)
+	if err != nil {
+		return err
+	}
+	export.FileURL = fileURL
+	export.Size = counter.n
+	return nil
+}
+
+// collect loads everything an export contains
+func (d *dataExportUsecase) collect(user *domain.User, batchSize int) (*exportData, error) {
+	data := &exportData{Profile: user, Media: make(map[uint64]string)}
+
+	var afterID uint64
+	for {
+		posts, err := d.exportRepo.GetPosts(user.ID, afterID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		data.Posts = append(data.Posts, posts...)
+		if len(posts) < batchSize {
+			break
+		}
+		afterID = posts[len(posts)-1].ID
+	}
+
+	afterID = 0
+	for {
+		comments, err := d.exportRepo.GetComments(user.ID, afterID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		data.Comments = append(data.Comments, comments...)
+		if len(comments) < batchSize {
+			break
+		}
+		afterID = comments[len(comments)-1].ID
+	}
+
+	afterID = 0
+	for {
+		likes, err := d.exportRepo.GetLikes(user.ID, afterID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		data.Likes = append(data.Likes, likes...)
+		if len(likes) < batchSize {
+			break
+		}
+		afterID = likes[len(likes)-1].ID
+	}
+
+	followers, err := d.userRepo.GetFollowers(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	data.Followers = toExportedUsers(followers)
+
+	following, err := d.userRepo.GetFollowing(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	data.Following = toExportedUsers(following)
+
+	return data, nil
+}
+
+// writeArchive writes the ZIP archive of an export to w. Images linked from
+// other sites are listed in posts.json but not copied.
+func (d *dataExportUsecase) writeArchive(w io.Writer, data *exportData, includeHTML bool) error {
+	zw := zip.NewWriter(w)
+
+	profileImages := []struct {
+		kind string
+		url  string
+	}{
+		{domain.ProfileImageAvatar, data.Profile.AvatarURL},
+		{domain.ProfileImageCover, data.Profile.CoverURL},
+	}
+	for _, image := range profileImages {
+		if image.url == "" {
+			continue
+		}
+		name := fmt.Sprintf("media/%s%s", image.kind, path.Ext(image.url))
+		if _, err := d.copyMedia(zw, name, image.url); err != nil {
+			return err
+		}
+	}
+
+	for _, post := range data.Posts {
+		if post.ImageURL == "" {
+			continue
+		}
+		name := fmt.Sprintf("media/post-%d%s", post.ID, path.Ext(post.ImageURL))
+		copied, err := d.copyMedia(zw, name, post.ImageURL)
+		if err != nil {
+			return err
+		}
+		if copied {
+			data.Media[post.ID] = name
+		}
+	}
+
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"posts.json", data.Posts},
+		{"comments.json", data.Comments},
+		{"likes.json", data.Likes},
+		{"followers.json", data.Followers},
+		{"following.json", data.Following},
+	}
+	for _, file := range files {
+		f, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.value); err != nil {
+			return err
+		}
+	}
+
+	if includeHTML {
+		f, err := zw.Create("index.html")
+		if err != nil {
+			return err
+		}
+		if err := exportTemplate.Execute(f, data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// copyMedia copies a stored image into the archive and reports whether the
+// store had it
+func (d *dataExportUsecase) copyMedia(zw *zip.Writer, name, url string) (bool, error) {
+	file, err := d.mediaStore.Open(url)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(f, file); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// downloadURL returns the signed download link of a completed export
+func (d *dataExportUsecase) downloadURL(export *domain.DataExport) string {
+	expires := export.ExpiresAt.Unix()
+	return fmt.Sprintf("%s/v1/exports/%d/download?expires=%d&signature=%s",
+		d.linkBaseURL, export.ID, expires, d.sign(export.ID, expires))
+}
+
+// sign returns the hex encoded HMAC-SHA256 of an export ID and link expiry.
+// The purpose prefix keeps signatures of other links from passing as these.
+func (d *dataExportUsecase) sign(id uint64, expires int64) string {
+	mac := hmac.New(sha256.New, d.signingKey)
+	fmt.Fprintf(mac, "export:%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func toExportedUsers(users []domain.User) []exportedUser {
+	exported := make([]exportedUser, len(users))
+	for i, user := range users {
+		exported[i] = exportedUser{
+			ID:        user.ID,
+			Username:  user.Username,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		}
+	}
+	return exported
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// exportTemplate renders the optional human readable page of an export
+var exportTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Data export of {{.Profile.Username}}</title>
+</head>
+<body>
+<h1>{{.Profile.FirstName}} {{.Profile.LastName}} (@{{.Profile.Username}})</h1>
+<p>Email: {{.Profile.Email}}<br>Joined: {{.Profile.CreatedAt.Format "2006-01-02"}}</p>
+
+<h2>Posts ({{len .Posts}})</h2>
+{{range .Posts}}<article>
+<p><small>{{.CreatedAt.Format "2006-01-02 15:04"}} · {{.Visibility}} · {{.Status}}</small></p>
+<p>{{.Content}}</p>
+{{with index $.Media .ID}}<img src="{{.}}" alt="" style="max-width: 480px">{{else}}{{with .ImageURL}}<p><a href="{{.}}">{{.}}</a></p>{{end}}{{end}}
+</article>
+{{end}}
+<h2>Comments ({{len .Comments}})</h2>
+<ul>
+{{range .Comments}}<li>{{.CreatedAt.Format "2006-01-02 15:04"}} on post {{.PostID}}: {{.Content}}</li>
+{{end}}</ul>
+
+<h2>Likes ({{len .Likes}})</h2>
+<ul>
+{{range .Likes}}<li>{{.CreatedAt.Format "2006-01-02 15:04"}} liked post {{.PostID}}</li>
+{{end}}</ul>
+
+<h2>Followers ({{len .Followers}})</h2>
+<ul>
+{{range .Followers}}<li>@{{.Username}} {{.FirstName}} {{.LastName}}</li>
+{{end}}</ul>
+
+<h2>Following ({{len .Following}})</h2>
+<ul>
+{{range .Following}}<li>@{{.Username}} {{.FirstName}} {{.LastName}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
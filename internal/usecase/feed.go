@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+// invalidateFeeds drops the cached posts of a user and the cached newsfeeds of
+// the user and their followers
+func invalidateFeeds(ctx context.Context, postCache cache.PostCache, userRepo domain.UserRepository, userID uint64) {
+	if err := postCache.DeleteUserPosts(ctx, userID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	followers, err := userRepo.GetFollowers(userID)
+	if err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+		return
+	}
+
+	feedOwners := make([]uint64, 0, len(followers)+1)
+	feedOwners = append(feedOwners, userID)
+	for _, follower := range followers {
+		feedOwners = append(feedOwners, follower.ID)
+	}
+	if err := postCache.DeleteNewsFeeds(ctx, feedOwners...); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+// invalidateShareCaches drops cached reposts and quotes and the feeds showing
+// them, after their original changed
+func invalidateShareCaches(ctx context.Context, postCache cache.PostCache, userRepo domain.UserRepository, shares []domain.Post) {
+	sharerIDs := make(map[uint64]bool)
+	for _, share := range shares {
+		if err := postCache.DeletePost(ctx, share.ID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		sharerIDs[share.UserID] = true
+	}
+	for sharerID := range sharerIDs {
+		invalidateFeeds(ctx, postCache, userRepo, sharerID)
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+// errPrivateAccount is returned when a viewer may not see a private account's content
+var errPrivateAccount = errors.New("this account is private")
+
+type followRequestUsecase struct {
+	requestRepo    domain.FollowRequestRepository
+	userCache      cache.UserCache
+	webhooks       domain.WebhookDispatcher
+	contextTimeout time.Duration
+}
+
+// NewFollowRequestUsecase creates a new follow request usecase
+func NewFollowRequestUsecase(frr domain.FollowRequestRepository, uc cache.UserCache, wd domain.WebhookDispatcher, timeout time.Duration) domain.FollowRequestUsecase {
+	return &followRequestUsecase{
+		requestRepo:    frr,
+		userCache:      uc,
+		webhooks:       wd,
+		contextTimeout: timeout,
+	}
+}
+
+func (f *followRequestUsecase) GetPending(userID uint64, page, limit int) ([]domain.FollowRequest, error) {
+	return f.requestRepo.GetPending(userID, page, limit)
+}
+
+func (f *followRequestUsecase) Approve(userID, requestID uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.contextTimeout)
+	defer cancel()
+
+	request, err := f.getOwnRequest(userID, requestID)
+	if err != nil {
+		return err
+	}
+
+	if err := f.requestRepo.Approve(request); err != nil {
+		return err
+	}
+
+	// Invalidate follow counts cache
+	for _, id := range []uint64{request.RequesterID, request.TargetID} {
+		if err := f.userCache.DeleteFollows(ctx, id); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	follow := &domain.Follow{FollowerID: request.RequesterID, FollowingID: request.TargetID, CreatedAt: time.Now()}
+	f.webhooks.Dispatch(domain.EventFollowCreated, follow, request.TargetID, request.RequesterID)
+
+	return nil
+}
+
+func (f *followRequestUsecase) Reject(userID, requestID uint64) error {
+	request, err := f.getOwnRequest(userID, requestID)
+	if err != nil {
+		return err
+	}
+
+	return f.requestRepo.Delete(request.RequesterID, request.TargetID)
+}
+
+// getOwnRequest loads a follow request addressed to userID
+func (f *followRequestUsecase) getOwnRequest(userID, requestID uint64) (*domain.FollowRequest, error) {
+	request, err := f.requestRepo.GetByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.TargetID != userID {
+		return nil, errors.New("follow request not found")
+	}
+	return request, nil
+}
+
+// checkAccountVisible returns an error unless viewerID may see the posts and
+// follows of owner. Users blocked in either direction do not find each other,
+// private accounts are only visible to themselves and approved followers.
+func checkAccountVisible(userRepo domain.UserRepository, blockRepo domain.BlockRepository, viewerID uint64, owner *domain.User) error {
+	if owner.ID == viewerID {
+		return nil
+	}
+
+	blocked, err := blockRepo.IsBlocked(viewerID, owner.ID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return domain.ErrUserNotFound
+	}
+	if !owner.IsPrivate {
+		return nil
+	}
+
+	following, err := userRepo.IsFollowing(viewerID, owner.ID)
+	if err != nil {
+		return err
+	}
+	if !following {
+		return errPrivateAccount
+	}
+	return nil
+}
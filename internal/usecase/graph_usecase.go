@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+)
+
+// mutualFriendsPreviewSize is the number of mutual friends shown in a relationship
+const mutualFriendsPreviewSize = 3
+
+type graphUsecase struct {
+	graphRepo domain.GraphRepository
+	userRepo  domain.UserRepository
+	blockRepo domain.BlockRepository
+}
+
+// NewGraphUsecase creates a new graph usecase
+func NewGraphUsecase(gr domain.GraphRepository, ur domain.UserRepository, br domain.BlockRepository) domain.GraphUsecase {
+	return &graphUsecase{
+		graphRepo: gr,
+		userRepo:  ur,
+		blockRepo: br,
+	}
+}
+
+// GetRelationship returns how userID relates to otherID. Anyone allowed to
+// see the follows of both users may ask, only the two users see the follow
+// requests, blocks, mutes and close friends between them.
+func (g *graphUsecase) GetRelationship(viewerID, userID, otherID uint64) (*domain.Relationship, error) {
+	if userID == otherID {
+		return nil, errors.New("a user has no relationship with itself")
+	}
+	if err := g.checkUsersVisible(viewerID, userID, otherID); err != nil {
+		return nil, err
+	}
+
+	edges, err := g.graphRepo.GetEdges(userID, otherID)
+	if err != nil {
+		return nil, err
+	}
+	mutualCount, err := g.graphRepo.CountMutualFriends(userID, otherID)
+	if err != nil {
+		return nil, err
+	}
+	mutualFriends, err := g.graphRepo.GetMutualFriends(userID, otherID, 1, mutualFriendsPreviewSize)
+	if err != nil {
+		return nil, err
+	}
+	degree, err := g.graphRepo.GetDegreeOfSeparation(userID, otherID, domain.MaxDegreesOfSeparation)
+	if err != nil {
+		return nil, err
+	}
+
+	relationship := &domain.Relationship{
+		UserID:             userID,
+		OtherUserID:        otherID,
+		Following:          edges.Following,
+		FollowedBy:         edges.FollowedBy,
+		IsFriend:           edges.Following && edges.FollowedBy,
+		MutualFriendsCount: mutualCount,
+		MutualFriends:      mutualFriends,
+	}
+	if degree > 0 {
+		relationship.Degree = &degree
+	}
+
+	// Requests and blocks concern both users, mutes and close friends are
+	// the private lists of userID
+	if viewerID == userID || viewerID == otherID {
+		relationship.FollowRequested = &edges.FollowRequested
+		relationship.FollowRequestReceived = &edges.FollowRequestReceived
+		relationship.Blocking = &edges.Blocking
+		relationship.BlockedBy = &edges.BlockedBy
+	}
+	if viewerID == userID {
+		relationship.Muting = &edges.Muting
+		relationship.CloseFriend = &edges.CloseFriend
+	}
+
+	return relationship, nil
+}
+
+// GetMutualFriends returns a page of the friends userID and otherID have in common
+func (g *graphUsecase) GetMutualFriends(viewerID, userID, otherID uint64, page, limit int) ([]domain.User, error) {
+	if err := g.checkUsersVisible(viewerID, userID, otherID); err != nil {
+		return nil, err
+	}
+
+	return g.graphRepo.GetMutualFriends(userID, otherID, page, limit)
+}
+
+// IsFriend reports whether two users follow each other
+func (g *graphUsecase) IsFriend(userID, otherID uint64) (bool, error) {
+	return g.graphRepo.IsFriend(userID, otherID)
+}
+
+// checkUsersVisible returns an error unless viewerID may see the follows of
+// every one of userIDs
+func (g *graphUsecase) checkUsersVisible(viewerID uint64, userIDs ...uint64) error {
+	for _, userID := range userIDs {
+		user, err := g.userRepo.GetByID(userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errors.New("user not found")
+		}
+
+		if err := checkAccountVisible(g.userRepo, g.blockRepo, viewerID, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,641 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxImportLineSize is the longest line an import file may contain
+const maxImportLineSize = 1024 * 1024
+
+type importUsecase struct {
+	importRepo   domain.ImportRepository
+	importStore  storage.Store
+	batchSize    int
+	maxErrors    int
+	leaseTimeout time.Duration
+}
+
+// NewImportUsecase creates a new import usecase committing batchSize records
+// at a time and keeping the first maxErrors validation errors of an import
+func NewImportUsecase(
+	ir domain.ImportRepository,
+	is storage.Store,
+	batchSize int,
+	maxErrors int,
+	leaseTimeout time.Duration,
+) domain.ImportUsecase {
+	return &importUsecase{
+		importRepo:   ir,
+		importStore:  is,
+		batchSize:    batchSize,
+		maxErrors:    maxErrors,
+		leaseTimeout: leaseTimeout,
+	}
+}
+
+// importLine is a record of an import file with its line number
+type importLine struct {
+	number int64
+	record domain.ImportRecord
+}
+
+// importState keeps what a dry run validated, since nothing of it is stored
+type importState struct {
+	known map[string]map[string]bool
+	taken map[string]bool
+}
+
+func newImportState() *importState {
+	state := &importState{
+		known: make(map[string]map[string]bool, len(domain.ImportKinds)),
+		taken: make(map[string]bool),
+	}
+	for _, kind := range domain.ImportKinds {
+		state.known[kind] = make(map[string]bool)
+	}
+	return state
+}
+
+// CreateImport stores an import file and queues its import. Records keep
+// their IDs in source, importing the same source again skips them.
+func (u *importUsecase) CreateImport(source string, dryRun bool, file io.Reader) (*domain.ImportJob, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, errors.New("import source is required")
+	}
+
+	token, _, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	fileURL, err := u.importStore.Put(fmt.Sprintf("import-%s.ndjson", token[:16]), file)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.ImportJob{
+		Source:  source,
+		FileURL: fileURL,
+		DryRun:  dryRun,
+		Status:  domain.ImportPending,
+		Stage:   domain.ImportKinds[0],
+	}
+	if err := u.importRepo.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (u *importUsecase) GetImport(id uint64) (*domain.ImportJob, error) {
+	job, err := u.importRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("import not found")
+	}
+	return job, nil
+}
+
+// ResumeImport queues a failed import again, continuing after its last
+// committed batch
+func (u *importUsecase) ResumeImport(id uint64) (*domain.ImportJob, error) {
+	job, err := u.GetImport(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.ImportFailed {
+		return nil, errors.New("only failed imports can be resumed")
+	}
+
+	job.Status = domain.ImportPending
+	job.Error = ""
+	if err := u.importRepo.Update(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// RunImport runs a pending or failed import right away instead of waiting
+// for the import worker
+func (u *importUsecase) RunImport(id uint64) (*domain.ImportJob, error) {
+	job, err := u.importRepo.ClaimByID(id, time.Now().Add(-u.leaseTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("import is completed or already running")
+	}
+	return job, u.run(job)
+}
+
+// ProcessNext claims a queued import and runs it. It reports whether an
+// import was claimed.
+func (u *importUsecase) ProcessNext() (bool, error) {
+	job, err := u.importRepo.Claim(time.Now().Add(-u.leaseTimeout))
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+	return true, u.run(job)
+}
+
+// run imports the file of job kind by kind, starting at its checkpoint
+func (u *importUsecase) run(job *domain.ImportJob) error {
+	// A dry run keeps what it validated in memory, so it always starts over
+	if job.DryRun {
+		*job = domain.ImportJob{
+			ID:        job.ID,
+			Source:    job.Source,
+			FileURL:   job.FileURL,
+			DryRun:    true,
+			Status:    job.Status,
+			Stage:     domain.ImportKinds[0],
+			Attempts:  job.Attempts,
+			CreatedAt: job.CreatedAt,
+		}
+	}
+	state := newImportState()
+
+	for job.Stage != domain.ImportStageDone {
+		if err := u.runStage(job, state); err != nil {
+			job.Status = domain.ImportFailed
+			job.Error = err.Error()
+			if err := u.importRepo.Update(job); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+			}
+			return fmt.Errorf("import %d failed at %s records: %w", job.ID, job.Stage, err)
+		}
+
+		job.Stage = nextImportStage(job.Stage)
+		job.Line = 0
+		if job.Stage == domain.ImportStageDone {
+			now := time.Now()
+			job.Status = domain.ImportCompleted
+			job.Error = ""
+			job.CompletedAt = &now
+		}
+		if err := u.importRepo.Update(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runStage reads the file and imports the records of the current kind in
+// batches. Malformed lines are reported while reading users, the first kind.
+func (u *importUsecase) runStage(job *domain.ImportJob, state *importState) error {
+	file, err := u.importStore.Open(job.FileURL)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+
+	firstStage := job.Stage == domain.ImportKinds[0]
+	batch := make([]importLine, 0, u.batchSize)
+	var number int64
+	for scanner.Scan() {
+		number++
+		if number <= job.Line || len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record domain.ImportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			if firstStage {
+				u.addError(job, number, fmt.Sprintf("invalid record: %v", err))
+			}
+			continue
+		}
+		if record.Type == job.Stage {
+			batch = append(batch, importLine{number: number, record: record})
+		} else if firstStage && !isImportKind(record.Type) {
+			u.addError(job, number, fmt.Sprintf("unknown record type %q", record.Type))
+		}
+
+		if len(batch) == u.batchSize {
+			if err := u.importBatch(job, state, batch, number); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return u.importBatch(job, state, batch, number)
+}
+
+// importBatch validates and stores a batch of records, checkpointing the job
+// at line number
+func (u *importUsecase) importBatch(job *domain.ImportJob, state *importState, batch []importLine, number int64) error {
+	job.Line = number
+
+	switch job.Stage {
+	case domain.ImportKindUser:
+		return u.importUsers(job, state, batch)
+	case domain.ImportKindPost:
+		return u.importPosts(job, state, batch)
+	case domain.ImportKindComment:
+		return u.importComments(job, state, batch)
+	case domain.ImportKindLike:
+		return u.importLikes(job, state, batch)
+	case domain.ImportKindFollow:
+		return u.importFollows(job, state, batch)
+	default:
+		return fmt.Errorf("unknown import stage %q", job.Stage)
+	}
+}
+
+// importUsers imports accounts without a usable password, their owners sign
+// in after resetting it
+func (u *importUsecase) importUsers(job *domain.ImportJob, state *importState, batch []importLine) error {
+	var sourceIDs, usernames, emails []string
+	for _, line := range batch {
+		sourceIDs = append(sourceIDs, string(line.record.ID))
+		usernames = append(usernames, line.record.Username)
+		emails = append(emails, line.record.Email)
+	}
+	mapped, err := u.resolve(job, state, domain.ImportKindUser, sourceIDs)
+	if err != nil {
+		return err
+	}
+	taken, err := u.importRepo.GetTakenUsernamesAndEmails(usernames, emails)
+	if err != nil {
+		return err
+	}
+
+	users := make([]domain.User, 0, len(batch))
+	importedIDs := make([]string, 0, len(batch))
+	for _, line := range batch {
+		r := line.record
+		if r.ID == "" || r.Username == "" || r.Email == "" {
+			u.addError(job, line.number, "user id, username and email are required")
+			continue
+		}
+		if _, ok := mapped[string(r.ID)]; ok {
+			job.Skipped++
+			continue
+		}
+		if taken[r.Username] || state.taken[r.Username] {
+			u.addError(job, line.number, fmt.Sprintf("username %q is taken", r.Username))
+			continue
+		}
+		if taken[r.Email] || state.taken[r.Email] {
+			u.addError(job, line.number, fmt.Sprintf("email %q is taken", r.Email))
+			continue
+		}
+
+		token, _, err := generateToken()
+		if err != nil {
+			return err
+		}
+		// The random password is never revealed, the minimum cost is enough
+		password, err := bcrypt.GenerateFromPassword([]byte(token), bcrypt.MinCost)
+		if err != nil {
+			return err
+		}
+
+		createdAt := importTime(r.CreatedAt)
+		users = append(users, domain.User{
+			Username:  r.Username,
+			Password:  string(password),
+			Email:     r.Email,
+			FirstName: r.FirstName,
+			LastName:  r.LastName,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+		importedIDs = append(importedIDs, string(r.ID))
+		mapped[string(r.ID)] = 0
+		state.taken[r.Username] = true
+		state.taken[r.Email] = true
+	}
+	job.UsersImported += int64(len(users))
+
+	if job.DryRun {
+		u.remember(state, domain.ImportKindUser, importedIDs)
+		return u.importRepo.Update(job)
+	}
+	return u.importRepo.ImportUsers(job, users, importedIDs)
+}
+
+func (u *importUsecase) importPosts(job *domain.ImportJob, state *importState, batch []importLine) error {
+	var sourceIDs, userIDs []string
+	for _, line := range batch {
+		sourceIDs = append(sourceIDs, string(line.record.ID))
+		userIDs = append(userIDs, string(line.record.UserID))
+	}
+	mapped, err := u.resolve(job, state, domain.ImportKindPost, sourceIDs)
+	if err != nil {
+		return err
+	}
+	users, err := u.resolve(job, state, domain.ImportKindUser, userIDs)
+	if err != nil {
+		return err
+	}
+
+	posts := make([]domain.Post, 0, len(batch))
+	importedIDs := make([]string, 0, len(batch))
+	for _, line := range batch {
+		r := line.record
+		if r.ID == "" || (r.Content == "" && r.ImageURL == "") {
+			u.addError(job, line.number, "post id and content or image_url are required")
+			continue
+		}
+		if _, ok := mapped[string(r.ID)]; ok {
+			job.Skipped++
+			continue
+		}
+		userID, ok := users[string(r.UserID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("post %s refers to unknown user %q", r.ID, r.UserID))
+			continue
+		}
+		visibility := r.Visibility
+		if visibility == "" {
+			visibility = domain.PostVisibilityPublic
+		}
+		if !isValidPostVisibility(visibility) {
+			u.addError(job, line.number, fmt.Sprintf("post %s has invalid visibility %q", r.ID, r.Visibility))
+			continue
+		}
+
+		createdAt := importTime(r.CreatedAt)
+		posts = append(posts, domain.Post{
+			UserID:     userID,
+			Content:    r.Content,
+			ImageURL:   r.ImageURL,
+			Visibility: visibility,
+			Status:     domain.PostStatusPublished,
+			CreatedAt:  createdAt,
+			UpdatedAt:  createdAt,
+		})
+		importedIDs = append(importedIDs, string(r.ID))
+		mapped[string(r.ID)] = 0
+	}
+	job.PostsImported += int64(len(posts))
+
+	if job.DryRun {
+		u.remember(state, domain.ImportKindPost, importedIDs)
+		return u.importRepo.Update(job)
+	}
+	return u.importRepo.ImportPosts(job, posts, importedIDs)
+}
+
+func (u *importUsecase) importComments(job *domain.ImportJob, state *importState, batch []importLine) error {
+	var sourceIDs, postIDs, userIDs []string
+	for _, line := range batch {
+		sourceIDs = append(sourceIDs, string(line.record.ID))
+		postIDs = append(postIDs, string(line.record.PostID))
+		userIDs = append(userIDs, string(line.record.UserID))
+	}
+	mapped, err := u.resolve(job, state, domain.ImportKindComment, sourceIDs)
+	if err != nil {
+		return err
+	}
+	posts, err := u.resolve(job, state, domain.ImportKindPost, postIDs)
+	if err != nil {
+		return err
+	}
+	users, err := u.resolve(job, state, domain.ImportKindUser, userIDs)
+	if err != nil {
+		return err
+	}
+
+	comments := make([]domain.Comment, 0, len(batch))
+	importedIDs := make([]string, 0, len(batch))
+	for _, line := range batch {
+		r := line.record
+		if r.ID == "" || r.Content == "" {
+			u.addError(job, line.number, "comment id and content are required")
+			continue
+		}
+		if _, ok := mapped[string(r.ID)]; ok {
+			job.Skipped++
+			continue
+		}
+		postID, ok := posts[string(r.PostID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("comment %s refers to unknown post %q", r.ID, r.PostID))
+			continue
+		}
+		userID, ok := users[string(r.UserID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("comment %s refers to unknown user %q", r.ID, r.UserID))
+			continue
+		}
+
+		createdAt := importTime(r.CreatedAt)
+		comments = append(comments, domain.Comment{
+			PostID:    postID,
+			UserID:    userID,
+			Content:   r.Content,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+		importedIDs = append(importedIDs, string(r.ID))
+		mapped[string(r.ID)] = 0
+	}
+	job.CommentsImported += int64(len(comments))
+
+	if job.DryRun {
+		u.remember(state, domain.ImportKindComment, importedIDs)
+		return u.importRepo.Update(job)
+	}
+	return u.importRepo.ImportComments(job, comments, importedIDs)
+}
+
+// importLikes imports likes, mapped by post and user since likes have no ID
+// of their own
+func (u *importUsecase) importLikes(job *domain.ImportJob, state *importState, batch []importLine) error {
+	var sourceIDs, postIDs, userIDs []string
+	for _, line := range batch {
+		sourceIDs = append(sourceIDs, likeSourceID(line.record))
+		postIDs = append(postIDs, string(line.record.PostID))
+		userIDs = append(userIDs, string(line.record.UserID))
+	}
+	mapped, err := u.resolve(job, state, domain.ImportKindLike, sourceIDs)
+	if err != nil {
+		return err
+	}
+	posts, err := u.resolve(job, state, domain.ImportKindPost, postIDs)
+	if err != nil {
+		return err
+	}
+	users, err := u.resolve(job, state, domain.ImportKindUser, userIDs)
+	if err != nil {
+		return err
+	}
+
+	likes := make([]domain.Like, 0, len(batch))
+	importedIDs := make([]string, 0, len(batch))
+	for _, line := range batch {
+		r := line.record
+		sourceID := likeSourceID(r)
+		if _, ok := mapped[sourceID]; ok {
+			job.Skipped++
+			continue
+		}
+		postID, ok := posts[string(r.PostID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("like refers to unknown post %q", r.PostID))
+			continue
+		}
+		userID, ok := users[string(r.UserID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("like refers to unknown user %q", r.UserID))
+			continue
+		}
+
+		likes = append(likes, domain.Like{
+			PostID:    postID,
+			UserID:    userID,
+			CreatedAt: importTime(r.CreatedAt),
+		})
+		importedIDs = append(importedIDs, sourceID)
+		mapped[sourceID] = 0
+	}
+	job.LikesImported += int64(len(likes))
+
+	if job.DryRun {
+		u.remember(state, domain.ImportKindLike, importedIDs)
+		return u.importRepo.Update(job)
+	}
+	return u.importRepo.ImportLikes(job, likes, importedIDs)
+}
+
+func (u *importUsecase) importFollows(job *domain.ImportJob, state *importState, batch []importLine) error {
+	var userIDs []string
+	for _, line := range batch {
+		userIDs = append(userIDs, string(line.record.FollowerID), string(line.record.FollowingID))
+	}
+	users, err := u.resolve(job, state, domain.ImportKindUser, userIDs)
+	if err != nil {
+		return err
+	}
+
+	follows := make([]domain.Follow, 0, len(batch))
+	seen := make(map[string]bool, len(batch))
+	for _, line := range batch {
+		r := line.record
+		followerID, ok := users[string(r.FollowerID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("follow refers to unknown user %q", r.FollowerID))
+			continue
+		}
+		followingID, ok := users[string(r.FollowingID)]
+		if !ok {
+			u.addError(job, line.number, fmt.Sprintf("follow refers to unknown user %q", r.FollowingID))
+			continue
+		}
+		if r.FollowerID == r.FollowingID {
+			u.addError(job, line.number, "users cannot follow themselves")
+			continue
+		}
+		key := string(r.FollowerID) + ":" + string(r.FollowingID)
+		if seen[key] {
+			job.Skipped++
+			continue
+		}
+		seen[key] = true
+
+		follows = append(follows, domain.Follow{
+			FollowerID:  followerID,
+			FollowingID: followingID,
+			CreatedAt:   importTime(r.CreatedAt),
+		})
+	}
+	job.FollowsImported += int64(len(follows))
+
+	if job.DryRun {
+		return u.importRepo.Update(job)
+	}
+	return u.importRepo.ImportFollows(job, follows)
+}
+
+// resolve returns the IDs given to the imported records of kind among
+// sourceIDs. For dry runs the records validated so far count as imported.
+func (u *importUsecase) resolve(job *domain.ImportJob, state *importState, kind string, sourceIDs []string) (map[string]uint64, error) {
+	targetIDs, err := u.importRepo.GetMappings(job.Source, kind, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if job.DryRun {
+		for _, sourceID := range sourceIDs {
+			if state.known[kind][sourceID] {
+				targetIDs[sourceID] = 0
+			}
+		}
+	}
+	return targetIDs, nil
+}
+
+// remember records the records a dry run validated
+func (u *importUsecase) remember(state *importState, kind string, sourceIDs []string) {
+	for _, sourceID := range sourceIDs {
+		state.known[kind][sourceID] = true
+	}
+}
+
+// addError counts a validation error and keeps it while under maxErrors
+func (u *importUsecase) addError(job *domain.ImportJob, number int64, message string) {
+	job.ErrorCount++
+	if job.ErrorCount > int64(u.maxErrors) {
+		return
+	}
+	if job.Errors != "" {
+		job.Errors += "\n"
+	}
+	job.Errors += fmt.Sprintf("line %d: %s", number, message)
+}
+
+func isImportKind(kind string) bool {
+	for _, k := range domain.ImportKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func nextImportStage(stage string) string {
+	for i, kind := range domain.ImportKinds {
+		if kind == stage && i+1 < len(domain.ImportKinds) {
+			return domain.ImportKinds[i+1]
+		}
+	}
+	return domain.ImportStageDone
+}
+
+func likeSourceID(record domain.ImportRecord) string {
+	return string(record.PostID) + ":" + string(record.UserID)
+}
+
+// importTime keeps the original timestamp of a record, records without one
+// are dated now
+func importTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
@@ -14,6 +14,9 @@ type likeUsecase struct {
 	likeCache   cache.LikeCache
 	postRepo    domain.PostRepository
 	userRepo    domain.UserRepository
+	webhooks    domain.WebhookDispatcher
+	blockRepo   domain.BlockRepository
+	audience    *AudienceResolver
 	contextTimeout time.Duration
 }
 
@@ -23,6 +26,9 @@ func NewLikeUsecase(
 	lc cache.LikeCache,
 	pr domain.PostRepository,
 	ur domain.UserRepository,
+	wd domain.WebhookDispatcher,
+	br domain.BlockRepository,
+	ar *AudienceResolver,
 	timeout time.Duration,
 ) domain.LikeUsecase {
 	return &likeUsecase{
@@ -30,6 +36,9 @@ func NewLikeUsecase(
 		likeCache:   lc,
 		postRepo:    pr,
 		userRepo:    ur,
+		webhooks:    wd,
+		blockRepo:   br,
+		audience:    ar,
 		contextTimeout: timeout,
 	}
 }
@@ -56,6 +65,20 @@ func (l *likeUsecase) LikePost(postID, userID uint64) error {
 		return errors.New("post not found")
 	}
 
+	// Blocked users cannot like each other's posts
+	blocked, err := l.blockRepo.IsBlocked(userID, post.UserID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errBlocked
+	}
+
+	// Only viewers of a post can like it
+	if err := l.audience.CheckPost(userID, post); err != nil {
+		return err
+	}
+
 	// Check if already liked
 	exists, err := l.likeRepo.Exists(postID, userID)
 	if err != nil {
@@ -88,6 +111,8 @@ func (l *likeUsecase) LikePost(postID, userID uint64) error {
 		// TODO: Add proper logging
 	}
 
+	l.webhooks.Dispatch(domain.EventLikeCreated, like, post.UserID, userID)
+
 	return nil
 }
 
@@ -104,6 +129,15 @@ func (l *likeUsecase) UnlikePost(postID, userID uint64) error {
 		return errors.New("post not liked")
 	}
 
+	// Get post to know whose webhooks to notify
+	post, err := l.postRepo.GetByID(postID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return errors.New("post not found")
+	}
+
 	// Delete like
 	if err := l.likeRepo.Delete(postID, userID); err != nil {
 		return err
@@ -121,13 +155,27 @@ func (l *likeUsecase) UnlikePost(postID, userID uint64) error {
 		// TODO: Add proper logging
 	}
 
+	l.webhooks.Dispatch(domain.EventLikeDeleted, &domain.Like{PostID: postID, UserID: userID}, post.UserID, userID)
+
 	return nil
 }
 
-func (l *likeUsecase) GetPostLikes(postID uint64, page, limit int) ([]domain.Like, error) {
+// GetPostLikes returns a page of likes of a post viewerID may see
+func (l *likeUsecase) GetPostLikes(postID, viewerID uint64, page, limit int) ([]domain.Like, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), l.contextTimeout)
 	defer cancel()
 
+	post, err := l.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.HiddenAt != nil {
+		return nil, errors.New("post not found")
+	}
+	if err := l.audience.CheckPost(viewerID, post); err != nil {
+		return nil, err
+	}
+
 	// Try to get from cache first
 	likes, err := l.likeCache.GetPostLikes(ctx, postID, page)
 	if err == nil {
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+var errTooManyAttempts = errors.New("too many failed login attempts, try again later")
+
+// LockoutPolicy configures login brute-force protection
+type LockoutPolicy struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+}
+
+// LoginGuard tracks failed logins per account and per IP. Every failure delays
+// the next attempt exponentially, and too many failures within the window lock
+// the account or IP out. Failures are tracked for unknown usernames too, so
+// lockouts do not reveal whether an account exists.
+type LoginGuard struct {
+	attemptCache cache.LoginAttemptCache
+	lockoutRepo  domain.LoginLockoutRepository
+	policy       LockoutPolicy
+}
+
+// NewLoginGuard creates a new login guard
+func NewLoginGuard(lac cache.LoginAttemptCache, lr domain.LoginLockoutRepository, policy LockoutPolicy) *LoginGuard {
+	return &LoginGuard{
+		attemptCache: lac,
+		lockoutRepo:  lr,
+		policy:       policy,
+	}
+}
+
+// Check returns an error while the account or IP is delayed or locked out
+func (g *LoginGuard) Check(ctx context.Context, username, ipAddress string) error {
+	for _, subject := range []string{accountSubject(username), ipSubject(ipAddress)} {
+		blockedFor, err := g.attemptCache.GetBlockedFor(ctx, subject)
+		if err != nil {
+			// Fail open so a cache outage does not lock everyone out
+			// TODO: Add proper logging
+			continue
+		}
+		if blockedFor > 0 {
+			return errTooManyAttempts
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login against the account and IP
+func (g *LoginGuard) RecordFailure(ctx context.Context, username, ipAddress string, userID *uint64) {
+	g.recordFailure(ctx, accountSubject(username), domain.LockoutScopeAccount, g.policy.MaxAccountFailures, username, ipAddress, userID)
+	g.recordFailure(ctx, ipSubject(ipAddress), domain.LockoutScopeIP, g.policy.MaxIPFailures, username, ipAddress, userID)
+}
+
+// RecordSuccess clears the failure count of the account
+func (g *LoginGuard) RecordSuccess(ctx context.Context, username string) {
+	if err := g.attemptCache.ResetFailures(ctx, accountSubject(username)); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+func (g *LoginGuard) recordFailure(ctx context.Context, subject, scope string, maxFailures int, username, ipAddress string, userID *uint64) {
+	failures, err := g.attemptCache.IncrementFailures(ctx, subject, g.policy.FailureWindow)
+	if err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+		return
+	}
+
+	if failures < int64(maxFailures) {
+		// Progressive delay before the next attempt is accepted
+		if err := g.attemptCache.Block(ctx, subject, g.delay(failures)); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		return
+	}
+
+	// Lock out and start counting again once the lockout expires
+	if err := g.attemptCache.Block(ctx, subject, g.policy.LockoutDuration); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+	if err := g.attemptCache.ResetFailures(ctx, subject); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	now := time.Now()
+	lockout := &domain.LoginLockout{
+		Scope:       scope,
+		Username:    normalizeUsername(username),
+		UserID:      userID,
+		IPAddress:   ipAddress,
+		Failures:    int(failures),
+		LockedUntil: now.Add(g.policy.LockoutDuration),
+		CreatedAt:   now,
+	}
+	if err := g.lockoutRepo.Create(lockout); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+// delay returns BaseDelay doubled for every failure after the first, capped at MaxDelay
+func (g *LoginGuard) delay(failures int64) time.Duration {
+	d := g.policy.BaseDelay
+	for i := int64(1); i < failures && d < g.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > g.policy.MaxDelay {
+		d = g.policy.MaxDelay
+	}
+	return d
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func accountSubject(username string) string {
+	return "user:" + normalizeUsername(username)
+}
+
+func ipSubject(ipAddress string) string {
+	return "ip:" + ipAddress
+}
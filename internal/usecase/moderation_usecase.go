@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+)
+
+type moderationUsecase struct {
+	reportRepo        domain.ReportRepository
+	postRepo          domain.PostRepository
+	commentRepo       domain.CommentRepository
+	blockRepo         domain.BlockRepository
+	audience          *AudienceResolver
+	postUsecase       domain.PostUsecase
+	commentUsecase    domain.CommentUsecase
+	adminUsecase      domain.AdminUsecase
+	autoHideThreshold int64
+	contextTimeout    time.Duration
+}
+
+// NewModerationUsecase creates a new moderation usecase. Targets are hidden
+// automatically once they have autoHideThreshold open reports, zero disables it.
+func NewModerationUsecase(
+	rr domain.ReportRepository,
+	pr domain.PostRepository,
+	cr domain.CommentRepository,
+	br domain.BlockRepository,
+	ar *AudienceResolver,
+	pu domain.PostUsecase,
+	cu domain.CommentUsecase,
+	au domain.AdminUsecase,
+	autoHideThreshold int,
+	timeout time.Duration,
+) domain.ModerationUsecase {
+	return &moderationUsecase{
+		reportRepo:        rr,
+		postRepo:          pr,
+		commentRepo:       cr,
+		blockRepo:         br,
+		audience:          ar,
+		postUsecase:       pu,
+		commentUsecase:    cu,
+		adminUsecase:      au,
+		autoHideThreshold: int64(autoHideThreshold),
+		contextTimeout:    timeout,
+	}
+}
+
+// ReportPost reports a post the reporter can see. Posts they cannot see are
+// not found, whether they exist or not.
+func (m *moderationUsecase) ReportPost(reporterID, postID uint64, reason, details string) (*domain.Report, error) {
+	post, err := m.visiblePost(reporterID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.report(reporterID, post.UserID, domain.ReportTargetPost, post.ID, reason, details)
+}
+
+// ReportComment reports a comment the reporter can see, on a post they can see
+func (m *moderationUsecase) ReportComment(reporterID, postID, commentID uint64, reason, details string) (*domain.Report, error) {
+	if _, err := m.visiblePost(reporterID, postID); err != nil {
+		return nil, err
+	}
+
+	comment, err := m.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.PostID != postID || comment.HiddenAt != nil {
+		return nil, errors.New("comment not found")
+	}
+
+	// Comments of users blocked with the reporter are hidden from them
+	blocked, err := m.blockRepo.IsBlocked(reporterID, comment.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("comment not found")
+	}
+
+	return m.report(reporterID, comment.UserID, domain.ReportTargetComment, comment.ID, reason, details)
+}
+
+// visiblePost loads a post for a reporter. Every reason not to see it gives
+// the same error, so reports do not reveal which posts exist.
+func (m *moderationUsecase) visiblePost(reporterID, postID uint64) (*domain.Post, error) {
+	post, err := m.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || m.audience.CheckPost(reporterID, post) != nil {
+		return nil, errors.New("post not found")
+	}
+	return post, nil
+}
+
+func (m *moderationUsecase) GetQueue(page, limit int) ([]domain.ModerationQueueItem, error) {
+	return m.reportRepo.GetQueue(page, limit)
+}
+
+func (m *moderationUsecase) GetTargetReports(targetType string, targetID uint64) ([]domain.Report, error) {
+	if !isValidReportTarget(targetType) {
+		return nil, errors.New("invalid report target")
+	}
+	return m.reportRepo.GetByTarget(targetType, targetID)
+}
+
+// TakeAction applies a moderation action to a target and closes its open reports
+func (m *moderationUsecase) TakeAction(moderatorID uint64, targetType string, targetID uint64, action, note string) (*domain.ModerationAction, error) {
+	authorID, err := m.targetAuthor(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.ReportStatusResolved
+	switch action {
+	case domain.ModerationActionHide:
+		err = m.setHidden(targetType, targetID, true)
+	case domain.ModerationActionRestore:
+		// Restoring means the reports were unfounded
+		err = m.setHidden(targetType, targetID, false)
+		status = domain.ReportStatusDismissed
+	case domain.ModerationActionRemove:
+		if targetType == domain.ReportTargetPost {
+			err = m.postUsecase.RemovePost(targetID)
+		} else {
+			err = m.commentUsecase.DeleteComment(targetID)
+		}
+	case domain.ModerationActionWarn:
+		// The recorded action is the warning, authors list them with GetWarnings
+	case domain.ModerationActionSuspend:
+		_, err = m.adminUsecase.SuspendUser(moderatorID, authorID, note)
+	case domain.ModerationActionDismiss:
+		status = domain.ReportStatusDismissed
+	default:
+		return nil, errors.New("invalid moderation action")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.reportRepo.ResolveTarget(targetType, targetID, status, moderatorID); err != nil {
+		return nil, err
+	}
+
+	moderationAction := &domain.ModerationAction{
+		ModeratorID:  &moderatorID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: authorID,
+		Action:       action,
+		Note:         note,
+		CreatedAt:    time.Now(),
+	}
+	if err := m.reportRepo.CreateAction(moderationAction); err != nil {
+		return nil, err
+	}
+
+	return moderationAction, nil
+}
+
+func (m *moderationUsecase) GetWarnings(userID uint64, page, limit int) ([]domain.ModerationAction, error) {
+	return m.reportRepo.GetActionsByUser(userID, domain.ModerationActionWarn, page, limit)
+}
+
+// report files a report and hides the target once it crosses the threshold
+func (m *moderationUsecase) report(reporterID, authorID uint64, targetType string, targetID uint64, reason, details string) (*domain.Report, error) {
+	if !isValidReportReason(reason) {
+		return nil, errors.New("invalid report reason")
+	}
+	if reporterID == authorID {
+		return nil, errors.New("cannot report your own content")
+	}
+
+	exists, err := m.reportRepo.Exists(reporterID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrAlreadyReported
+	}
+
+	report := &domain.Report{
+		ReporterID: reporterID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Details:    details,
+		Status:     domain.ReportStatusOpen,
+		CreatedAt:  time.Now(),
+	}
+	// A duplicate that raced past the check above is rejected here, before
+	// it could count towards hiding the target
+	if err := m.reportRepo.Create(report); err != nil {
+		return nil, err
+	}
+
+	if m.autoHideThreshold > 0 {
+		if err := m.autoHide(authorID, targetType, targetID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	return report, nil
+}
+
+// autoHide hides a target with too many open reports until a moderator reviews
+// it. The reports stay open so the target remains in the queue.
+func (m *moderationUsecase) autoHide(authorID uint64, targetType string, targetID uint64) error {
+	count, err := m.reportRepo.CountOpen(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if count < m.autoHideThreshold {
+		return nil
+	}
+
+	if err := m.setHidden(targetType, targetID, true); err != nil {
+		return err
+	}
+
+	return m.reportRepo.CreateAction(&domain.ModerationAction{
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: authorID,
+		Action:       domain.ModerationActionHide,
+		Note:         fmt.Sprintf("automatically hidden after %d reports", count),
+		CreatedAt:    time.Now(),
+	})
+}
+
+func (m *moderationUsecase) setHidden(targetType string, targetID uint64, hidden bool) error {
+	if targetType == domain.ReportTargetPost {
+		return m.postUsecase.SetPostHidden(targetID, hidden)
+	}
+	return m.commentUsecase.SetCommentHidden(targetID, hidden)
+}
+
+// targetAuthor returns the author of a post or comment, hidden or not
+func (m *moderationUsecase) targetAuthor(targetType string, targetID uint64) (uint64, error) {
+	switch targetType {
+	case domain.ReportTargetPost:
+		post, err := m.postRepo.GetByID(targetID)
+		if err != nil {
+			return 0, err
+		}
+		if post == nil {
+			return 0, errors.New("post not found")
+		}
+		return post.UserID, nil
+	case domain.ReportTargetComment:
+		comment, err := m.commentRepo.GetByID(targetID)
+		if err != nil {
+			return 0, err
+		}
+		if comment == nil {
+			return 0, errors.New("comment not found")
+		}
+		return comment.UserID, nil
+	default:
+		return 0, errors.New("invalid report target")
+	}
+}
+
+func isValidReportTarget(targetType string) bool {
+	return targetType == domain.ReportTargetPost || targetType == domain.ReportTargetComment
+}
+
+func isValidReportReason(reason string) bool {
+	switch reason {
+	case domain.ReportReasonSpam,
+		domain.ReportReasonHarassment,
+		domain.ReportReasonHateSpeech,
+		domain.ReportReasonViolence,
+		domain.ReportReasonNudity,
+		domain.ReportReasonMisinformation,
+		domain.ReportReasonOther:
+		return true
+	}
+	return false
+}
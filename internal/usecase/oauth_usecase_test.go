@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/oidc"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/oidc/oidctest"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/token"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type memoryStateCache struct {
+	states map[string]*domain.OAuthState
+}
+
+func (c *memoryStateCache) SetState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error {
+	c.states[state] = data
+	return nil
+}
+
+func (c *memoryStateCache) PopState(ctx context.Context, state string) (*domain.OAuthState, error) {
+	data, ok := c.states[state]
+	if !ok {
+		return nil, errors.New("state not found")
+	}
+	delete(c.states, state)
+	return data, nil
+}
+
+type memoryIdentityRepo struct {
+	identities []domain.UserIdentity
+}
+
+func (r *memoryIdentityRepo) Create(identity *domain.UserIdentity) error {
+	identity.ID = uint64(len(r.identities) + 1)
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *memoryIdentityRepo) GetByProviderSubject(provider, subject string) (*domain.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			return &r.identities[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryIdentityRepo) GetByUserID(userID uint64) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+// memoryUserRepo implements the user lookups social login needs, any other
+// method panics through the nil embedded interface
+type memoryUserRepo struct {
+	domain.UserRepository
+	users []*domain.User
+}
+
+func (r *memoryUserRepo) Create(user *domain.User) error {
+	user.ID = uint64(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *memoryUserRepo) GetByID(id uint64) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepo) GetByEmail(email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepo) IsUsernameTaken(username string, userID uint64, now time.Time) (bool, error) {
+	for _, user := range r.users {
+		if user.Username == username && user.ID != userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type noopUserCache struct {
+	cache.UserCache
+}
+
+func (noopUserCache) SetUser(ctx context.Context, user *domain.User) error {
+	return nil
+}
+
+type noopVerification struct {
+	domain.VerificationUsecase
+}
+
+func (noopVerification) SendEmailVerification(userID uint64) error {
+	return nil
+}
+
+type oauthTest struct {
+	server     *oidctest.Server
+	users      *memoryUserRepo
+	identities *memoryIdentityRepo
+	usecase    domain.OAuthUsecase
+}
+
+func newOAuthTest(t *testing.T) *oauthTest {
+	t.Helper()
+	server := oidctest.NewServer("client-id", "client-secret")
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider("test", &config.OAuthProviderConfig{
+		Issuer:       server.Issuer(),
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://app.example.com/v1/oauth/test/callback",
+		Scopes:       []string{"openid", "email"},
+	})
+
+	ot := &oauthTest{
+		server:     server,
+		users:      &memoryUserRepo{},
+		identities: &memoryIdentityRepo{},
+	}
+	ot.usecase = NewOAuthUsecase(
+		map[string]*oidc.Provider{"test": provider},
+		&memoryStateCache{states: make(map[string]*domain.OAuthState)},
+		ot.identities,
+		ot.users,
+		noopUserCache{},
+		noopVerification{},
+		token.NewManager("secret", time.Hour, time.Minute),
+		10*time.Minute,
+		5*time.Second,
+	)
+	return ot
+}
+
+// login runs a whole social login, consenting with claims at the provider
+func (ot *oauthTest) login(t *testing.T, claims jwt.MapClaims) (*domain.LoginResult, error) {
+	t.Helper()
+	authURL, binding, err := ot.usecase.AuthorizationURL("test")
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error: %v", err)
+	}
+	state, code, err := ot.server.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatalf("Authorize() error: %v", err)
+	}
+	return ot.usecase.HandleCallback("test", state, code, binding)
+}
+
+func TestOAuthLoginCreatesUser(t *testing.T) {
+	ot := newOAuthTest(t)
+	claims := jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com", "email_verified": true, "preferred_username": "Jane.Doe"}
+
+	result, err := ot.login(t, claims)
+	if err != nil {
+		t.Fatalf("HandleCallback() error: %v", err)
+	}
+	if result.AccessToken == "" || result.TwoFactorRequired {
+		t.Fatalf("HandleCallback() = %+v, want an access token", result)
+	}
+	if len(ot.users.users) != 1 || len(ot.identities.identities) != 1 {
+		t.Fatalf("got %d users and %d identities, want 1 each", len(ot.users.users), len(ot.identities.identities))
+	}
+	user := ot.users.users[0]
+	if user.Username != "janedoe" || !user.EmailVerified {
+		t.Errorf("created user %q verified=%v, want janedoe verified", user.Username, user.EmailVerified)
+	}
+
+	// The second login finds the linked identity
+	if _, err := ot.login(t, claims); err != nil {
+		t.Fatalf("second HandleCallback() error: %v", err)
+	}
+	if len(ot.users.users) != 1 || len(ot.identities.identities) != 1 {
+		t.Errorf("second login created another user or identity")
+	}
+}
+
+func TestOAuthLoginLinksExistingAccount(t *testing.T) {
+	tests := []struct {
+		name             string
+		localVerified    bool
+		providerVerified bool
+		wantLinked       bool
+	}{
+		{"both verified", true, true, true},
+		{"local email unverified", false, true, false},
+		{"provider email unverified", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ot := newOAuthTest(t)
+			ot.users.Create(&domain.User{Username: "jane", Email: "jane@example.com", EmailVerified: tt.localVerified, Role: domain.RoleUser})
+
+			_, err := ot.login(t, jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com", "email_verified": tt.providerVerified})
+
+			if linked := err == nil; linked != tt.wantLinked {
+				t.Fatalf("HandleCallback() error = %v, want linked %v", err, tt.wantLinked)
+			}
+			if len(ot.users.users) != 1 {
+				t.Errorf("got %d users, want the existing account only", len(ot.users.users))
+			}
+			wantIdentities := 0
+			if tt.wantLinked {
+				wantIdentities = 1
+			}
+			if len(ot.identities.identities) != wantIdentities {
+				t.Errorf("got %d identities, want %d", len(ot.identities.identities), wantIdentities)
+			}
+		})
+	}
+}
+
+func TestOAuthLoginTwoFactorChallenge(t *testing.T) {
+	ot := newOAuthTest(t)
+	ot.users.Create(&domain.User{Username: "jane", Email: "jane@example.com", EmailVerified: true, TwoFactorEnabled: true, Role: domain.RoleUser})
+
+	result, err := ot.login(t, jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("HandleCallback() error: %v", err)
+	}
+	if !result.TwoFactorRequired || result.ChallengeToken == "" || result.AccessToken != "" {
+		t.Errorf("HandleCallback() = %+v, want a 2FA challenge only", result)
+	}
+}
+
+func TestOAuthCallbackRequiresBrowserBinding(t *testing.T) {
+	ot := newOAuthTest(t)
+	claims := jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com", "email_verified": true}
+
+	authURL, binding, err := ot.usecase.AuthorizationURL("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code, err := ot.server.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A victim's browser following the attacker's callback URL has no binding
+	// or a different one
+	if _, err := ot.usecase.HandleCallback("test", state, code, ""); !errors.Is(err, errInvalidLoginState) {
+		t.Fatalf("HandleCallback() without binding error = %v, want errInvalidLoginState", err)
+	}
+	// The state was consumed by the failed attempt
+	if _, err := ot.usecase.HandleCallback("test", state, code, binding); !errors.Is(err, errInvalidLoginState) {
+		t.Fatalf("HandleCallback() replay error = %v, want errInvalidLoginState", err)
+	}
+	if len(ot.users.users) != 0 {
+		t.Errorf("rejected callbacks created %d users", len(ot.users.users))
+	}
+}
+
+func TestOAuthCallbackRejectsInvalidState(t *testing.T) {
+	ot := newOAuthTest(t)
+	authURL, binding, err := ot.usecase.AuthorizationURL("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code, err := ot.server.Authorize(authURL, jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ot.usecase.HandleCallback("test", "unknown-state", code, binding); !errors.Is(err, errInvalidLoginState) {
+		t.Errorf("HandleCallback() unknown state error = %v, want errInvalidLoginState", err)
+	}
+	if _, err := ot.usecase.HandleCallback("other", state, code, binding); !errors.Is(err, errUnknownProvider) {
+		t.Errorf("HandleCallback() unknown provider error = %v, want errUnknownProvider", err)
+	}
+}
+
+func TestOAuthCallbackRejectsTokenProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"nonce mismatch", jwt.MapClaims{"nonce": "other-nonce"}},
+		{"wrong audience", jwt.MapClaims{"aud": "other-client"}},
+		{"expired", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ot := newOAuthTest(t)
+			claims := jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com", "email_verified": true}
+			for name, value := range tt.claims {
+				claims[name] = value
+			}
+
+			if _, err := ot.login(t, claims); err == nil {
+				t.Fatal("HandleCallback() accepted an invalid id token")
+			}
+			if len(ot.users.users) != 0 {
+				t.Errorf("rejected login created %d users", len(ot.users.users))
+			}
+		})
+	}
+}
+
+func TestOAuthCallbackRejectsWrongVerifier(t *testing.T) {
+	ot := newOAuthTest(t)
+	authURL, binding, err := ot.usecase.AuthorizationURL("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An attacker starting their own login cannot reuse a code issued for it
+	// with someone else's PKCE challenge
+	u, _ := url.Parse(authURL)
+	query := u.Query()
+	_, otherChallenge, _ := oidc.GenerateCodeVerifier()
+	query.Set("code_challenge", otherChallenge)
+	u.RawQuery = query.Encode()
+
+	state, code, err := ot.server.Authorize(u.String(), jwt.MapClaims{"sub": "subject-1", "email": "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ot.usecase.HandleCallback("test", state, code, binding); err == nil {
+		t.Error("HandleCallback() accepted a code issued for another PKCE challenge")
+	}
+}
@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
@@ -14,15 +13,33 @@ type postUsecase struct {
 	postRepo    domain.PostRepository
 	postCache   cache.PostCache
 	userRepo    domain.UserRepository
+	webhooks    domain.WebhookDispatcher
+	contentFilter domain.ContentFilterUsecase
+	audience    *AudienceResolver
+	restoreWindow time.Duration
 	contextTimeout time.Duration
 }
 
-// NewPostUsecase creates a new post usecase
-func NewPostUsecase(pr domain.PostRepository, pc cache.PostCache, ur domain.UserRepository, timeout time.Duration) domain.PostUsecase {
+// NewPostUsecase creates a new post usecase. Deleted posts can be restored
+// for restoreWindow.
+func NewPostUsecase(
+	pr domain.PostRepository,
+	pc cache.PostCache,
+	ur domain.UserRepository,
+	wd domain.WebhookDispatcher,
+	cf domain.ContentFilterUsecase,
+	ar *AudienceResolver,
+	restoreWindow time.Duration,
+	timeout time.Duration,
+) domain.PostUsecase {
 	return &postUsecase{
 		postRepo:    pr,
 		postCache:   pc,
 		userRepo:    ur,
+		webhooks:    wd,
+		contentFilter: cf,
+		audience:    ar,
+		restoreWindow: restoreWindow,
 		contextTimeout: timeout,
 	}
 }
@@ -40,40 +57,72 @@ func (p *postUsecase) CreatePost(post *domain.Post) error {
 		return errors.New("user not found")
 	}
 
+	if post.Visibility == "" {
+		post.Visibility = domain.PostVisibilityPublic
+	}
+	if !isValidPostVisibility(post.Visibility) {
+		return errors.New("invalid post visibility")
+	}
+
+	// Run content filters, rejected posts are not written. Reposts have no content.
+	var screening *domain.ContentScreening
+	if !post.IsRepost() {
+		screening, err = p.contentFilter.Screen(post.UserID, domain.ReportTargetPost, post.Content)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Set timestamps
 	now := time.Now()
 	post.CreatedAt = now
 	post.UpdatedAt = now
+	post.Status = domain.PostStatusPublished
+	post.PublishAt = nil
 
 	// Create post in database
 	if err := p.postRepo.Create(post); err != nil {
 		return err
 	}
+	p.contentFilter.Attach(screening, post.ID)
+
+	p.announce(ctx, post)
+
+	return nil
+}
 
+// announce caches a newly published post, invalidates the author's cached
+// posts and notifies webhooks. Drafts and scheduled posts go through it when
+// they are published.
+func (p *postUsecase) announce(ctx context.Context, post *domain.Post) {
 	// Cache post
 	if err := p.postCache.SetPost(ctx, post); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
 
-	// Invalidate user's posts cache and newsfeed cache for followers
-	pattern := fmt.Sprintf("user:%d:posts:*", post.UserID)
-	if err := p.postCache.DeletePattern(ctx, pattern); err != nil {
+	// Invalidate user's posts cache
+	if err := p.postCache.DeleteUserPosts(ctx, post.UserID); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
 
-	return nil
+	p.webhooks.Dispatch(domain.EventPostCreated, post, post.UserID)
 }
 
-func (p *postUsecase) GetPost(id uint64) (*domain.Post, error) {
+// GetPost returns a post if its visibility and author allow viewerID to see it
+func (p *postUsecase) GetPost(id, viewerID uint64) (*domain.Post, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
 	defer cancel()
 
-	// Try to get from cache first
+	// Try to get from cache first. The cached copy of a shared original may
+	// be outdated, so the original is checked again.
 	post, err := p.postCache.GetPost(ctx, id)
-	if err == nil && post != nil {
-		return post, nil
+	if err == nil && post != nil && post.HiddenAt == nil && p.originalStillShareable(post) {
+		if err := p.audience.CheckPost(viewerID, post); err != nil {
+			return nil, err
+		}
+		return p.filterBlocked(viewerID, post)
 	}
 
 	// If not in cache, get from database
@@ -81,9 +130,14 @@ func (p *postUsecase) GetPost(id uint64) (*domain.Post, error) {
 	if err != nil {
 		return nil, err
 	}
-	if post == nil {
+	// Hidden posts are only reachable through moderation, and reposts go with
+	// their original
+	if post == nil || post.HiddenAt != nil || (post.IsRepost() && post.OriginalPost == nil) {
 		return nil, errors.New("post not found")
 	}
+	if err := p.audience.CheckPost(viewerID, post); err != nil {
+		return nil, err
+	}
 
 	// Cache post
 	if err := p.postCache.SetPost(ctx, post); err != nil {
@@ -91,32 +145,61 @@ func (p *postUsecase) GetPost(id uint64) (*domain.Post, error) {
 		// TODO: Add proper logging
 	}
 
-	return post, nil
+	return p.filterBlocked(viewerID, post)
 }
 
-func (p *postUsecase) GetUserPosts(userID uint64, page, limit int) ([]domain.Post, error) {
+// originalStillShareable reports whether the original of a cached share may
+// still be shown with it. Posts without an original always pass.
+func (p *postUsecase) originalStillShareable(post *domain.Post) bool {
+	if post.OriginalPostID == nil || post.OriginalPost == nil {
+		return true
+	}
+	shareable, err := p.postRepo.IsShareable(*post.OriginalPostID)
+	return err == nil && shareable
+}
+
+// filterBlocked applies FilterBlocked to a single post
+func (p *postUsecase) filterBlocked(viewerID uint64, post *domain.Post) (*domain.Post, error) {
+	posts, err := p.audience.FilterBlocked(viewerID, []domain.Post{*post})
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, errors.New("post not found")
+	}
+	return &posts[0], nil
+}
+
+// GetUserPosts returns the posts of userID that viewerID may see. Pages are
+// cached per audience rather than per viewer.
+func (p *postUsecase) GetUserPosts(userID, viewerID uint64, page, limit int) ([]domain.Post, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
 	defer cancel()
 
+	audience, err := p.audience.Resolve(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Try to get from cache first
-	posts, err := p.postCache.GetUserPosts(ctx, userID, page)
+	posts, err := p.postCache.GetUserPosts(ctx, userID, audience, page)
 	if err == nil {
-		return posts, nil
+		return p.audience.FilterBlocked(viewerID, posts)
 	}
 
 	// If not in cache, get from database
-	posts, err = p.postRepo.GetByUserID(userID, page, limit)
+	posts, err = p.postRepo.GetByUserID(userID, audienceVisibilities[audience], page, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	// Cache posts
-	if err := p.postCache.SetUserPosts(ctx, userID, page, posts); err != nil {
+	if err := p.postCache.SetUserPosts(ctx, userID, audience, page, posts); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
 
-	return posts, nil
+	return p.audience.FilterBlocked(viewerID, posts)
 }
 
 func (p *postUsecase) UpdatePost(post *domain.Post) error {
@@ -134,14 +217,41 @@ func (p *postUsecase) UpdatePost(post *domain.Post) error {
 	if existingPost.UserID != post.UserID {
 		return errors.New("unauthorized")
 	}
+	// Drafts and scheduled posts are edited through UpdateDraft
+	if existingPost.IsPending() {
+		return errors.New("post not found")
+	}
+	if post.Visibility != "" && !isValidPostVisibility(post.Visibility) {
+		return errors.New("invalid post visibility")
+	}
+
+	// Edits go through the content filters like new posts. Unchanged content
+	// was screened already and would only match itself as a duplicate.
+	var screening *domain.ContentScreening
+	if post.Content != existingPost.Content {
+		screening, err = p.contentFilter.Screen(post.UserID, domain.ReportTargetPost, post.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only the content fields change, moderation state and timestamps are kept
+	visibilityChanged := post.Visibility != "" && post.Visibility != existingPost.Visibility
+	existingPost.Content = post.Content
+	existingPost.ImageURL = post.ImageURL
+	if post.Visibility != "" {
+		existingPost.Visibility = post.Visibility
+	}
 
 	// Update timestamp
-	post.UpdatedAt = time.Now()
+	existingPost.UpdatedAt = time.Now()
 
 	// Update in database
-	if err := p.postRepo.Update(post); err != nil {
+	if err := p.postRepo.Update(existingPost); err != nil {
 		return err
 	}
+	p.contentFilter.Attach(screening, existingPost.ID)
+	*post = *existingPost
 
 	// Update in cache
 	if err := p.postCache.SetPost(ctx, post); err != nil {
@@ -149,13 +259,19 @@ func (p *postUsecase) UpdatePost(post *domain.Post) error {
 		// TODO: Add proper logging
 	}
 
-	// Invalidate user's posts cache and newsfeed cache
-	pattern := fmt.Sprintf("user:%d:posts:*", post.UserID)
-	if err := p.postCache.DeletePattern(ctx, pattern); err != nil {
-		// Log error but don't return it
-		// TODO: Add proper logging
+	// Invalidate user's posts cache, and the newsfeeds when the audience changed
+	if visibilityChanged {
+		invalidateFeeds(ctx, p.postCache, p.userRepo, post.UserID)
+		p.invalidateShares(ctx, post.ID)
+	} else {
+		if err := p.postCache.DeleteUserPosts(ctx, post.UserID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
 	}
 
+	p.webhooks.Dispatch(domain.EventPostUpdated, post, post.UserID)
+
 	return nil
 }
 
@@ -163,6 +279,21 @@ func (p *postUsecase) DeletePost(id uint64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
 	defer cancel()
 
+	// Get post to know whose webhooks to notify
+	post, err := p.postRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return errors.New("post not found")
+	}
+
+	// Reposts are deleted with the post and quotes lose it, so their feeds go stale
+	shares, err := p.postRepo.GetShares(id)
+	if err != nil {
+		return err
+	}
+
 	// Delete from database
 	if err := p.postRepo.Delete(id); err != nil {
 		return err
@@ -173,10 +304,81 @@ func (p *postUsecase) DeletePost(id uint64) error {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
+	invalidateShareCaches(ctx, p.postCache, p.userRepo, shares)
+
+	// The original's share count changed
+	if post.OriginalPostID != nil {
+		if err := p.postCache.DeletePost(ctx, *post.OriginalPostID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	p.webhooks.Dispatch(domain.EventPostDeleted, post, post.UserID)
 
 	return nil
 }
 
+// RemovePost deletes a post on behalf of moderation. The post is hidden as
+// well, which keeps its author from restoring it.
+func (p *postUsecase) RemovePost(id uint64) error {
+	post, err := p.postRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return domain.ErrPostNotFound
+	}
+
+	if post.HiddenAt == nil {
+		now := time.Now()
+		if err := p.postRepo.SetHidden(id, &now); err != nil {
+			return err
+		}
+	}
+
+	return p.DeletePost(id)
+}
+
+// RestorePost undeletes a post of userID, with the reposts deleted along with
+// it, within the restore window. Posts hidden by moderation stay deleted.
+func (p *postUsecase) RestorePost(id, userID uint64) (*domain.Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
+	defer cancel()
+
+	post, err := p.postRepo.GetDeleted(id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.UserID != userID {
+		return nil, errors.New("post not found")
+	}
+	if post.HiddenAt != nil {
+		return nil, errors.New("post cannot be restored")
+	}
+	if time.Since(post.DeletedAt.Time) > p.restoreWindow {
+		return nil, errors.New("restore window has expired")
+	}
+
+	if err := p.postRepo.Restore(post); err != nil {
+		return nil, err
+	}
+
+	// The post and its reposts are back in feeds
+	invalidateFeeds(ctx, p.postCache, p.userRepo, post.UserID)
+	p.invalidateShares(ctx, id)
+
+	// The original's share count changed
+	if post.OriginalPostID != nil {
+		if err := p.postCache.DeletePost(ctx, *post.OriginalPostID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	return p.postRepo.GetByID(id)
+}
+
 func (p *postUsecase) GetNewsFeed(userID uint64, page, limit int) ([]domain.Post, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
 	defer cancel()
@@ -184,7 +386,7 @@ func (p *postUsecase) GetNewsFeed(userID uint64, page, limit int) ([]domain.Post
 	// Try to get from cache first
 	posts, err := p.postCache.GetNewsFeed(ctx, userID, page)
 	if err == nil {
-		return posts, nil
+		return p.audience.FilterBlocked(userID, posts)
 	}
 
 	// If not in cache, get from database
@@ -199,5 +401,325 @@ func (p *postUsecase) GetNewsFeed(userID uint64, page, limit int) ([]domain.Post
 		// TODO: Add proper logging
 	}
 
-	return posts, nil
+	return p.audience.FilterBlocked(userID, posts)
+}
+
+// SetPostHidden hides a post from feeds or restores it, dropping it from the
+// post cache and from the cached pages of everyone who may have it in a feed
+func (p *postUsecase) SetPostHidden(id uint64, hidden bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
+	defer cancel()
+
+	post, err := p.postRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return errors.New("post not found")
+	}
+
+	var hiddenAt *time.Time
+	if hidden {
+		now := time.Now()
+		hiddenAt = &now
+	}
+	if err := p.postRepo.SetHidden(id, hiddenAt); err != nil {
+		return err
+	}
+
+	// Delete from cache
+	if err := p.postCache.DeletePost(ctx, id); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	invalidateFeeds(ctx, p.postCache, p.userRepo, post.UserID)
+	p.invalidateShares(ctx, id)
+
+	return nil
+}
+
+// SharePost reposts a post, or quotes it when content is given. Only public
+// posts of public accounts can be shared. Sharing a repost shares its original.
+func (p *postUsecase) SharePost(userID, originalPostID uint64, content, visibility string) (*domain.Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
+	defer cancel()
+
+	original, err := p.postRepo.GetByID(originalPostID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.HiddenAt != nil {
+		return nil, errors.New("post not found")
+	}
+	if original.IsRepost() {
+		// The original is only loaded while it can still be shared
+		original = original.OriginalPost
+		if original == nil {
+			return nil, errors.New("post not found")
+		}
+	}
+	if err := p.audience.CheckPost(userID, original); err != nil {
+		return nil, err
+	}
+
+	author, err := p.userRepo.GetByID(original.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Visibility != domain.PostVisibilityPublic || author == nil || author.IsPrivate {
+		return nil, errors.New("post cannot be shared")
+	}
+
+	if content == "" {
+		repost, err := p.postRepo.GetRepost(userID, original.ID)
+		if err != nil {
+			return nil, err
+		}
+		if repost != nil {
+			return nil, errors.New("post already reposted")
+		}
+	}
+
+	post := &domain.Post{
+		UserID:         userID,
+		Content:        content,
+		Visibility:     visibility,
+		OriginalPostID: &original.ID,
+	}
+	if err := p.CreatePost(post); err != nil {
+		return nil, err
+	}
+	post.OriginalPost = original
+
+	// The original's share count changed
+	if err := p.postCache.DeletePost(ctx, original.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return post, nil
+}
+
+// UndoRepost deletes the user's repost of a post
+func (p *postUsecase) UndoRepost(userID, originalPostID uint64) error {
+	repost, err := p.postRepo.GetRepost(userID, originalPostID)
+	if err != nil {
+		return err
+	}
+	if repost == nil {
+		return errors.New("post not reposted")
+	}
+
+	return p.DeletePost(repost.ID)
+}
+
+// invalidateShares drops the cached reposts and quotes of a post and the feeds
+// showing them
+func (p *postUsecase) invalidateShares(ctx context.Context, postID uint64) {
+	shares, err := p.postRepo.GetShares(postID)
+	if err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+		return
+	}
+	invalidateShareCaches(ctx, p.postCache, p.userRepo, shares)
+}
+
+
+// CreateDraft stores a post without publishing it. Posts with a PublishAt are
+// scheduled and screened by the content filters right away, drafts are
+// screened when they are published.
+func (p *postUsecase) CreateDraft(post *domain.Post) error {
+	// Verify user exists
+	user, err := p.userRepo.GetByID(post.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if post.Visibility == "" {
+		post.Visibility = domain.PostVisibilityPublic
+	}
+	if !isValidPostVisibility(post.Visibility) {
+		return errors.New("invalid post visibility")
+	}
+
+	screening, err := p.scheduleDraft(post)
+	if err != nil {
+		return err
+	}
+
+	// Set timestamps
+	now := time.Now()
+	post.CreatedAt = now
+	post.UpdatedAt = now
+
+	if err := p.postRepo.Create(post); err != nil {
+		return err
+	}
+	p.contentFilter.Attach(screening, post.ID)
+
+	return nil
+}
+
+func (p *postUsecase) GetDrafts(userID uint64, page, limit int) ([]domain.Post, error) {
+	return p.postRepo.GetDrafts(userID, page, limit)
+}
+
+func (p *postUsecase) GetDraft(id, userID uint64) (*domain.Post, error) {
+	return p.getOwnDraft(id, userID)
+}
+
+// UpdateDraft replaces the content of a draft or scheduled post. Setting
+// PublishAt (re)schedules it, leaving it unset turns it back into a draft.
+func (p *postUsecase) UpdateDraft(post *domain.Post) error {
+	existingPost, err := p.getOwnDraft(post.ID, post.UserID)
+	if err != nil {
+		return err
+	}
+	if post.Visibility != "" && !isValidPostVisibility(post.Visibility) {
+		return errors.New("invalid post visibility")
+	}
+
+	existingPost.Content = post.Content
+	existingPost.ImageURL = post.ImageURL
+	if post.Visibility != "" {
+		existingPost.Visibility = post.Visibility
+	}
+	existingPost.PublishAt = post.PublishAt
+
+	screening, err := p.scheduleDraft(existingPost)
+	if err != nil {
+		return err
+	}
+
+	// Update timestamp
+	existingPost.UpdatedAt = time.Now()
+
+	// The scheduler may have published the post since it was loaded
+	updated, err := p.postRepo.UpdateDraft(existingPost)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("draft not found")
+	}
+	p.contentFilter.Attach(screening, existingPost.ID)
+	*post = *existingPost
+
+	return nil
+}
+
+func (p *postUsecase) DeleteDraft(id, userID uint64) error {
+	if _, err := p.getOwnDraft(id, userID); err != nil {
+		return err
+	}
+
+	deleted, err := p.postRepo.DeleteDraft(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("draft not found")
+	}
+	return nil
+}
+
+// PublishDraft publishes a draft or scheduled post now
+func (p *postUsecase) PublishDraft(id, userID uint64) (*domain.Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
+	defer cancel()
+
+	draft, err := p.getOwnDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled posts were screened when they were scheduled
+	var screening *domain.ContentScreening
+	if draft.Status == domain.PostStatusDraft {
+		screening, err = p.contentFilter.Screen(draft.UserID, domain.ReportTargetPost, draft.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	post, err := p.postRepo.PublishDraft(id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, errors.New("draft not found")
+	}
+	p.contentFilter.Attach(screening, post.ID)
+
+	p.announce(ctx, post)
+
+	return post, nil
+}
+
+// PublishDuePosts publishes up to limit scheduled posts whose time has come
+// and returns how many were published. Concurrent callers never publish the
+// same post twice.
+func (p *postUsecase) PublishDuePosts(limit int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.contextTimeout)
+	defer cancel()
+
+	posts, err := p.postRepo.PublishDue(time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range posts {
+		p.announce(ctx, &posts[i])
+	}
+
+	return len(posts), nil
+}
+
+// GetPostRevisions returns the previous versions of a post to a viewer who may see it
+func (p *postUsecase) GetPostRevisions(postID, viewerID uint64, page, limit int) ([]domain.PostRevision, error) {
+	post, err := p.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.HiddenAt != nil {
+		return nil, errors.New("post not found")
+	}
+	if err := p.audience.CheckPost(viewerID, post); err != nil {
+		return nil, err
+	}
+
+	return p.postRepo.GetRevisions(postID, page, limit)
+}
+
+// scheduleDraft sets the status of an unpublished post from its PublishAt
+// and screens the content of scheduled posts
+func (p *postUsecase) scheduleDraft(post *domain.Post) (*domain.ContentScreening, error) {
+	if post.PublishAt == nil {
+		post.Status = domain.PostStatusDraft
+		return nil, nil
+	}
+
+	if !post.PublishAt.After(time.Now()) {
+		return nil, errors.New("publish_at must be in the future")
+	}
+	post.Status = domain.PostStatusScheduled
+
+	return p.contentFilter.Screen(post.UserID, domain.ReportTargetPost, post.Content)
+}
+
+// getOwnDraft loads an unpublished post written by userID
+func (p *postUsecase) getOwnDraft(id, userID uint64) (*domain.Post, error) {
+	post, err := p.postRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.UserID != userID || !post.IsPending() {
+		return nil, errors.New("draft not found")
+	}
+	return post, nil
 }
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+)
+
+type privacyUsecase struct {
+	privacyRepo domain.PrivacyRepository
+	userRepo    domain.UserRepository
+	blockRepo   domain.BlockRepository
+}
+
+// NewPrivacyUsecase creates a new privacy usecase
+func NewPrivacyUsecase(pr domain.PrivacyRepository, ur domain.UserRepository, br domain.BlockRepository) domain.PrivacyUsecase {
+	return &privacyUsecase{
+		privacyRepo: pr,
+		userRepo:    ur,
+		blockRepo:   br,
+	}
+}
+
+// GetSettings returns the privacy settings of a user, or the defaults if
+// they never changed them
+func (p *privacyUsecase) GetSettings(userID uint64) (*domain.PrivacySettings, error) {
+	settings, err := p.privacyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return domain.DefaultPrivacySettings(userID), nil
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes the audiences set in update and keeps the others
+func (p *privacyUsecase) UpdateSettings(userID uint64, update *domain.PrivacyUpdate) (*domain.PrivacySettings, error) {
+	settings, err := p.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		value  *string
+		target *string
+	}{
+		{update.Email, &settings.Email},
+		{update.Birthday, &settings.Birthday},
+		{update.Location, &settings.Location},
+		{update.Website, &settings.Website},
+		{update.Pronouns, &settings.Pronouns},
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		switch *field.value {
+		case domain.FieldAudiencePublic, domain.FieldAudienceFollowers, domain.FieldAudienceOnlyMe:
+			*field.target = *field.value
+		default:
+			return nil, errors.New("invalid audience")
+		}
+	}
+
+	settings.UpdatedAt = time.Now()
+	if err := p.privacyRepo.Save(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetProfileAccess returns which optional fields of a user's profile viewerID
+// can see. Users see all of their own profile, followers see the fields
+// shared with followers and everyone else only the public fields. Users
+// blocked in either direction do not find each other's profile.
+func (p *privacyUsecase) GetProfileAccess(viewerID, userID uint64) (*domain.ProfileAccess, error) {
+	if viewerID == userID {
+		access := domain.FullProfileAccess
+		return &access, nil
+	}
+
+	blocked, err := p.blockRepo.IsBlocked(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, domain.ErrUserNotFound
+	}
+
+	settings, err := p.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	isFollower, err := p.userRepo.IsFollowing(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := settings.AccessFor(isFollower)
+	return &access, nil
+}
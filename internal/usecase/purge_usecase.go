@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+)
+
+type purgeUsecase struct {
+	userRepo      domain.UserRepository
+	postRepo      domain.PostRepository
+	commentRepo   domain.CommentRepository
+	mediaStore    storage.Store
+	restoreWindow time.Duration
+}
+
+// NewPurgeUsecase creates a new purge usecase removing rows deleted longer
+// than restoreWindow ago
+func NewPurgeUsecase(
+	ur domain.UserRepository,
+	pr domain.PostRepository,
+	cr domain.CommentRepository,
+	ms storage.Store,
+	restoreWindow time.Duration,
+) domain.PurgeUsecase {
+	return &purgeUsecase{
+		userRepo:      ur,
+		postRepo:      pr,
+		commentRepo:   cr,
+		mediaStore:    ms,
+		restoreWindow: restoreWindow,
+	}
+}
+
+// PurgeExpired purges up to limit expired posts, comments and users each, and
+// returns how many rows were purged. Post and profile images are deleted once
+// their post or user is gone.
+func (p *purgeUsecase) PurgeExpired(limit int) (int, error) {
+	deletedBefore := time.Now().Add(-p.restoreWindow)
+	purged := 0
+
+	posts, err := p.postRepo.GetPurgeable(deletedBefore, limit)
+	if err != nil {
+		return purged, err
+	}
+	for _, post := range posts {
+		mediaURLs, err := p.postRepo.GetMediaURLs(post.ID)
+		if err != nil {
+			return purged, err
+		}
+		if err := p.postRepo.Purge(post.ID); err != nil {
+			return purged, err
+		}
+		purged++
+
+		for _, url := range mediaURLs {
+			if err := p.mediaStore.Delete(url); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+			}
+		}
+	}
+
+	comments, err := p.commentRepo.GetPurgeable(deletedBefore, limit)
+	if err != nil {
+		return purged, err
+	}
+	for _, comment := range comments {
+		if err := p.commentRepo.Purge(comment.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	users, err := p.userRepo.GetPurgeable(deletedBefore, limit)
+	if err != nil {
+		return purged, err
+	}
+	for _, user := range users {
+		mediaURLs, err := p.userRepo.GetMediaURLs(user.ID)
+		if err != nil {
+			return purged, err
+		}
+		if err := p.userRepo.Purge(user.ID); err != nil {
+			return purged, err
+		}
+		purged++
+
+		for _, url := range mediaURLs {
+			if err := p.mediaStore.Delete(url); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+			}
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// confirmIdentity checks the password of a signed in user before a sensitive
+// change, and a TOTP or recovery code when 2FA is enabled, so that an access
+// token alone cannot take over the account
+func confirmIdentity(twoFactor domain.TwoFactorUsecase, user *domain.User, password, code string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("invalid password")
+	}
+	if !user.TwoFactorEnabled {
+		return nil
+	}
+
+	if code == "" {
+		return errors.New("two-factor code required")
+	}
+	valid, err := twoFactor.VerifyCode(user.ID, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid two-factor code")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+)
+
+type suggestionUsecase struct {
+	suggestionRepo  domain.SuggestionRepository
+	suggestionCache cache.SuggestionCache
+	size            int
+	previewSize     int
+	ttl             time.Duration
+	contextTimeout  time.Duration
+}
+
+// NewSuggestionUsecase creates a new suggestion usecase. Up to size
+// suggestions are kept per user for ttl, each with up to previewSize mutual
+// connections.
+func NewSuggestionUsecase(
+	sr domain.SuggestionRepository,
+	sc cache.SuggestionCache,
+	size int,
+	previewSize int,
+	ttl time.Duration,
+	timeout time.Duration,
+) domain.SuggestionUsecase {
+	return &suggestionUsecase{
+		suggestionRepo:  sr,
+		suggestionCache: sc,
+		size:            size,
+		previewSize:     previewSize,
+		ttl:             ttl,
+		contextTimeout:  timeout,
+	}
+}
+
+// GetSuggestions returns up to limit of the suggestions precomputed for a
+// user, computing them if there are none yet. Accounts followed, requested
+// or blocked since the suggestions were computed are left out.
+func (s *suggestionUsecase) GetSuggestions(userID uint64, limit int) ([]domain.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.contextTimeout)
+	defer cancel()
+
+	suggestions, err := s.suggestionCache.GetSuggestions(ctx, userID)
+	if err != nil {
+		suggestions, err = s.Refresh(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	candidateIDs := make([]uint64, len(suggestions))
+	for i, suggestion := range suggestions {
+		candidateIDs[i] = suggestion.User.ID
+	}
+	suggestableIDs, err := s.suggestionRepo.FilterSuggestable(userID, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	suggestable := make(map[uint64]bool, len(suggestableIDs))
+	for _, id := range suggestableIDs {
+		suggestable[id] = true
+	}
+
+	result := make([]domain.Suggestion, 0, limit)
+	for _, suggestion := range suggestions {
+		if len(result) == limit {
+			break
+		}
+		if suggestable[suggestion.User.ID] {
+			result = append(result, suggestion)
+		}
+	}
+	return result, nil
+}
+
+// Refresh computes the suggestions of a user and caches them
+func (s *suggestionUsecase) Refresh(userID uint64) ([]domain.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.contextTimeout)
+	defer cancel()
+
+	suggestions, err := s.compute(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.suggestionCache.SetSuggestions(ctx, userID, suggestions, s.ttl); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return suggestions, nil
+}
+
+// RefreshBatch refreshes the suggestions of up to batchSize users after
+// afterID. It returns the last user refreshed and how many users were.
+func (s *suggestionUsecase) RefreshBatch(afterID uint64, batchSize int) (uint64, int, error) {
+	userIDs, err := s.suggestionRepo.GetUserIDs(afterID, batchSize)
+	if err != nil {
+		return afterID, 0, err
+	}
+
+	for i, userID := range userIDs {
+		if _, err := s.Refresh(userID); err != nil {
+			return afterID, i, err
+		}
+		afterID = userID
+	}
+	return afterID, len(userIDs), nil
+}
+
+// compute ranks friends of friends by mutual connections and fills the
+// remaining places with popular accounts
+func (s *suggestionUsecase) compute(userID uint64) ([]domain.Suggestion, error) {
+	candidates, err := s.suggestionRepo.GetFriendsOfFriends(userID, s.size)
+	if err != nil {
+		return nil, err
+	}
+
+	candidateIDs := make([]uint64, len(candidates))
+	for i, candidate := range candidates {
+		candidateIDs[i] = candidate.UserID
+	}
+	previews, err := s.suggestionRepo.GetMutualPreviews(userID, candidateIDs, s.previewSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users following few or no accounts have few friends of friends
+	if len(candidates) < s.size {
+		popularIDs, err := s.suggestionRepo.GetPopular(userID, candidateIDs, s.size-len(candidates))
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range popularIDs {
+			candidates = append(candidates, domain.SuggestionCandidate{UserID: id})
+			candidateIDs = append(candidateIDs, id)
+		}
+	}
+
+	users, err := s.suggestionRepo.GetUsers(candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uint64]domain.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	suggestions := make([]domain.Suggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		user, ok := usersByID[candidate.UserID]
+		if !ok {
+			continue
+		}
+		reason := domain.SuggestionReasonMutual
+		if candidate.MutualCount == 0 {
+			reason = domain.SuggestionReasonPopular
+		}
+		suggestions = append(suggestions, domain.Suggestion{
+			User:          user,
+			Reason:        reason,
+			MutualCount:   candidate.MutualCount,
+			MutualPreview: previews[candidate.UserID],
+		})
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recoveryCodeCount is the number of recovery codes issued on enrollment
+const recoveryCodeCount = 10
+
+// totpSkew is the number of time steps accepted before or after the current one
+const totpSkew = 1
+
+type twoFactorUsecase struct {
+	twoFactorRepo  domain.TwoFactorRepository
+	userRepo       domain.UserRepository
+	userCache      cache.UserCache
+	issuer         string
+	contextTimeout time.Duration
+}
+
+// NewTwoFactorUsecase creates a new two-factor usecase. issuer is shown in
+// authenticator apps next to the account name.
+func NewTwoFactorUsecase(tr domain.TwoFactorRepository, ur domain.UserRepository, uc cache.UserCache, issuer string, timeout time.Duration) domain.TwoFactorUsecase {
+	return &twoFactorUsecase{
+		twoFactorRepo:  tr,
+		userRepo:       ur,
+		userCache:      uc,
+		issuer:         issuer,
+		contextTimeout: timeout,
+	}
+}
+
+// Enroll generates a new TOTP secret and recovery codes. 2FA is not enabled
+// until the user confirms a code from their authenticator app.
+func (t *twoFactorUsecase) Enroll(userID uint64) (*domain.TwoFactorEnrollment, error) {
+	user, err := t.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate recovery codes, only their hashes are stored
+	codes := make([]string, recoveryCodeCount)
+	codeHashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		codeHashes[i] = hashToken(code)
+	}
+	if err := t.twoFactorRepo.ReplaceRecoveryCodes(user.ID, codeHashes); err != nil {
+		return nil, err
+	}
+
+	// Store pending secret
+	user.TwoFactorSecret = secret
+	user.UpdatedAt = time.Now()
+	if err := t.userRepo.Update(user, "two_factor_secret"); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret:        secret,
+		URI:           totp.URI(t.issuer, user.Username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (t *twoFactorUsecase) Confirm(userID uint64, code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.contextTimeout)
+	defer cancel()
+
+	user, err := t.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactorEnabled {
+		return errors.New("two-factor authentication already enabled")
+	}
+	if user.TwoFactorSecret == "" {
+		return errors.New("two-factor enrollment not started")
+	}
+
+	counter, ok := totp.Validate(user.TwoFactorSecret, normalizeCode(code), time.Now(), totpSkew)
+	if !ok {
+		return errors.New("invalid two-factor code")
+	}
+
+	// The confirming code counts as used, like any later one
+	advanced, err := t.twoFactorRepo.AdvanceCounter(user.ID, counter)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return errors.New("invalid two-factor code")
+	}
+
+	// Enable 2FA
+	user.TwoFactorEnabled = true
+	user.UpdatedAt = time.Now()
+	if err := t.userRepo.Update(user, "two_factor_enabled"); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := t.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+func (t *twoFactorUsecase) Disable(userID uint64, password string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.contextTimeout)
+	defer cancel()
+
+	user, err := t.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled && user.TwoFactorSecret == "" {
+		return errors.New("two-factor authentication not enabled")
+	}
+
+	// Require the password again before removing a factor
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("invalid password")
+	}
+
+	if err := t.twoFactorRepo.DeleteRecoveryCodes(user.ID); err != nil {
+		return err
+	}
+
+	// Disable 2FA
+	// The last used time step is kept, codes of a later enrollment come after it
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	user.UpdatedAt = time.Now()
+	if err := t.userRepo.Update(user, "two_factor_enabled", "two_factor_secret"); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := t.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// VerifyCode checks a TOTP code or an unused recovery code for a user with 2FA
+// enabled. Each TOTP time step and each recovery code can only be used once.
+func (t *twoFactorUsecase) VerifyCode(userID uint64, code string) (bool, error) {
+	user, err := t.getUser(userID)
+	if err != nil {
+		return false, err
+	}
+	if !user.TwoFactorEnabled {
+		return false, nil
+	}
+
+	code = normalizeCode(code)
+	if counter, ok := totp.Validate(user.TwoFactorSecret, code, time.Now(), totpSkew); ok {
+		return t.twoFactorRepo.AdvanceCounter(user.ID, counter)
+	}
+
+	return t.twoFactorRepo.UseRecoveryCode(user.ID, hashToken(code))
+}
+
+func (t *twoFactorUsecase) getUser(userID uint64) (*domain.User, error) {
+	user, err := t.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(b)
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeCode strips whitespace and lowercases user entered codes
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.Join(strings.Fields(code), ""))
+}
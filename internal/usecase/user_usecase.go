@@ -1,26 +1,92 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
 	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/storage"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/token"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var errInvalidCredentials = errors.New("invalid username or password")
+
+var errAccountSuspended = errors.New("account suspended")
+
+// profileImageTypes maps the accepted profile image types to their file extension
+var profileImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// dummyPasswordHash is compared against when a username does not exist so that
+// unknown usernames take as long to reject as wrong passwords
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
 type userUsecase struct {
 	userRepo    domain.UserRepository
 	userCache   cache.UserCache
+	webhooks    domain.WebhookDispatcher
+	verification domain.VerificationUsecase
+	loginGuard  *LoginGuard
+	twoFactor   domain.TwoFactorUsecase
+	tokens      *token.Manager
+	blockRepo   domain.BlockRepository
+	followRequests domain.FollowRequestRepository
+	postRepo    domain.PostRepository
+	postCache   cache.PostCache
+	mediaStore  storage.Store
+	maxImageSize int64
+	usernameCooldown    time.Duration
+	usernameReservation time.Duration
 	contextTimeout time.Duration
 }
 
 // NewUserUsecase creates a new user usecase
-func NewUserUsecase(ur domain.UserRepository, uc cache.UserCache, timeout time.Duration) domain.UserUsecase {
+func NewUserUsecase(
+	ur domain.UserRepository,
+	uc cache.UserCache,
+	wd domain.WebhookDispatcher,
+	vu domain.VerificationUsecase,
+	lg *LoginGuard,
+	tfu domain.TwoFactorUsecase,
+	tm *token.Manager,
+	br domain.BlockRepository,
+	frr domain.FollowRequestRepository,
+	pr domain.PostRepository,
+	pc cache.PostCache,
+	ms storage.Store,
+	maxImageSize int64,
+	usernameCooldown time.Duration,
+	usernameReservation time.Duration,
+	timeout time.Duration,
+) domain.UserUsecase {
 	return &userUsecase{
 		userRepo:    ur,
 		userCache:   uc,
+		webhooks:    wd,
+		verification: vu,
+		loginGuard:  lg,
+		twoFactor:   tfu,
+		tokens:      tm,
+		blockRepo:   br,
+		followRequests: frr,
+		postRepo:    pr,
+		postCache:   pc,
+		mediaStore:  ms,
+		maxImageSize: maxImageSize,
+		usernameCooldown:    usernameCooldown,
+		usernameReservation: usernameReservation,
 		contextTimeout: timeout,
 	}
 }
@@ -29,17 +95,17 @@ func (u *userUsecase) Register(user *domain.User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
-	// Check if username exists
-	existingUser, err := u.userRepo.GetByUsername(user.Username)
+	// Check if username exists or is reserved
+	taken, err := u.userRepo.IsUsernameTaken(user.Username, 0, time.Now())
 	if err != nil {
 		return err
 	}
-	if existingUser != nil {
+	if taken {
 		return errors.New("username already exists")
 	}
 
 	// Check if email exists
-	existingUser, err = u.userRepo.GetByEmail(user.Email)
+	existingUser, err := u.userRepo.GetByEmail(user.Email)
 	if err != nil {
 		return err
 	}
@@ -60,36 +126,130 @@ func (u *userUsecase) Register(user *domain.User) error {
 	user.UpdatedAt = now
 
 	// Create user
+	user.Role = domain.RoleUser
+	user.SuspendedAt = nil
+	user.EmailVerified = false
+	user.EmailVerifiedAt = nil
 	if err := u.userRepo.Create(user); err != nil {
 		return err
 	}
 
+	// Send verification email, the user can request a new one if this fails
+	if err := u.verification.SendEmailVerification(user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
 	// Cache user
 	return u.userCache.SetUser(ctx, user)
 }
 
-func (u *userUsecase) Login(username, password string) (string, error) {
+// Login verifies the password and, for users with 2FA enabled, the code. A user
+// who passes the password step without a valid code gets a challenge token to
+// complete the login with CompleteTwoFactorLogin.
+func (u *userUsecase) Login(username, password, code, ipAddress string) (*domain.LoginResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
+	// Reject attempts while the account or IP is delayed or locked out
+	if err := u.loginGuard.Check(ctx, username, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Get user from database
 	user, err := u.userRepo.GetByUsername(username)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if user == nil {
-		return "", errors.New("invalid username or password")
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		u.loginGuard.RecordFailure(ctx, username, ipAddress, nil)
+		return nil, errInvalidCredentials
 	}
 
 	// Compare passwords
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid username or password")
+		u.loginGuard.RecordFailure(ctx, username, ipAddress, &user.ID)
+		return nil, errInvalidCredentials
 	}
 
-	// TODO: Generate JWT token
-	// For now, return a placeholder
-	return "jwt_token", nil
+	// Suspended users are told so only after proving the password
+	if user.IsSuspended() {
+		return nil, errAccountSuspended
+	}
+
+	if user.TwoFactorEnabled {
+		valid := false
+		if code != "" {
+			valid, err = u.twoFactor.VerifyCode(user.ID, code)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				u.loginGuard.RecordFailure(ctx, username, ipAddress, &user.ID)
+			}
+		}
+		if !valid {
+			challengeToken, err := u.tokens.GenerateChallengeToken(user.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &domain.LoginResult{ChallengeToken: challengeToken, TwoFactorRequired: true}, nil
+		}
+	}
+
+	u.loginGuard.RecordSuccess(ctx, username)
+
+	return u.issueAccessToken(user)
+}
+
+// CompleteTwoFactorLogin exchanges a challenge token and a valid code for an access token
+func (u *userUsecase) CompleteTwoFactorLogin(challengeToken, code, ipAddress string) (*domain.LoginResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	userID, err := u.tokens.Parse(challengeToken, token.TypeChallenge)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+	if user.IsSuspended() {
+		return nil, errAccountSuspended
+	}
+
+	// Codes count towards the same lockout as passwords
+	if err := u.loginGuard.Check(ctx, user.Username, ipAddress); err != nil {
+		return nil, err
+	}
+
+	valid, err := u.twoFactor.VerifyCode(user.ID, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		u.loginGuard.RecordFailure(ctx, user.Username, ipAddress, &user.ID)
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	u.loginGuard.RecordSuccess(ctx, user.Username)
+
+	return u.issueAccessToken(user)
+}
+
+func (u *userUsecase) issueAccessToken(user *domain.User) (*domain.LoginResult, error) {
+	accessToken, err := u.tokens.GenerateAccessToken(user.ID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{AccessToken: accessToken}, nil
 }
 
 func (u *userUsecase) GetProfile(id uint64) (*domain.User, error) {
@@ -120,54 +280,401 @@ func (u *userUsecase) GetProfile(id uint64) (*domain.User, error) {
 	return user, nil
 }
 
-func (u *userUsecase) UpdateProfile(user *domain.User) error {
+// GetByUsername returns the user with username, or the user who gave it up
+// while it is still reserved for them
+func (u *userUsecase) GetByUsername(username string) (*domain.User, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user, err = u.userRepo.GetByFormerUsername(username, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// ChangeUsername renames a user at most once per usernameCooldown. The former
+// username stays reserved for the user for usernameReservation, so nobody
+// else can take it and links to it keep leading to the user.
+func (u *userUsecase) ChangeUsername(id uint64, username string) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
-	// Update timestamp
-	user.UpdatedAt = time.Now()
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Username == username {
+		return nil, errors.New("username is unchanged")
+	}
 
-	// If password is being updated, hash it
-	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-		if err != nil {
-			return err
+	now := time.Now()
+	if user.UsernameChangedAt != nil {
+		if next := user.UsernameChangedAt.Add(u.usernameCooldown); now.Before(next) {
+			return nil, fmt.Errorf("username can be changed again after %s", next.Format(time.RFC3339))
+		}
+	}
+
+	taken, err := u.userRepo.IsUsernameTaken(username, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("username already exists")
+	}
+
+	change := &domain.UsernameChange{
+		UserID:        id,
+		Username:      user.Username,
+		ReservedUntil: now.Add(u.usernameReservation),
+		CreatedAt:     now,
+	}
+	user.Username = username
+	user.UsernameChangedAt = &now
+	user.UpdatedAt = now
+	if err := u.userRepo.ChangeUsername(user, change); err != nil {
+		return nil, err
+	}
+
+	// Drop the cached user, the copy read here may miss concurrent changes
+	if err := u.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return user, nil
+}
+
+// UpdateProfile updates the profile fields set in update and keeps the others.
+// Account fields such as the password, role, suspension and 2FA state are
+// never changed.
+func (u *userUsecase) UpdateProfile(id uint64, update *domain.ProfileUpdate) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if update.Website != nil && *update.Website != "" {
+		website, err := url.Parse(*update.Website)
+		if err != nil || (website.Scheme != "http" && website.Scheme != "https") || website.Host == "" {
+			return nil, errors.New("website must be an http or https URL")
+		}
+	}
+
+	fields := []struct {
+		value  *string
+		target *string
+	}{
+		{update.FirstName, &user.FirstName},
+		{update.LastName, &user.LastName},
+		{update.Bio, &user.Bio},
+		{update.Website, &user.Website},
+		{update.Location, &user.Location},
+		{update.Pronouns, &user.Pronouns},
+	}
+	for _, field := range fields {
+		if field.value != nil {
+			*field.target = *field.value
 		}
-		user.Password = string(hashedPassword)
 	}
+	if update.Birthday != nil {
+		user.Birthday = *update.Birthday
+	}
+
+	// Update timestamp
+	user.UpdatedAt = time.Now()
 
 	// Update in database
-	if err := u.userRepo.Update(user); err != nil {
+	if err := u.userRepo.Update(user, "first_name", "last_name", "bio", "website", "location", "pronouns", "birthday"); err != nil {
+		return nil, err
+	}
+
+	// Drop the cached user, the copy read here may miss concurrent changes
+	if err := u.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return user, nil
+}
+
+// ChangePassword sets a new password once the current one, and a 2FA code when
+// enabled, are confirmed
+func (u *userUsecase) ChangePassword(id uint64, currentPassword, newPassword, code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
 		return err
 	}
+	if user == nil {
+		return errors.New("user not found")
+	}
 
-	// Update in cache
-	return u.userCache.SetUser(ctx, user)
+	if err := confirmIdentity(u.twoFactor, user, currentPassword, code); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(user, "password"); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := u.userCache.DeleteUser(ctx, id); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
 }
 
-func (u *userUsecase) DeleteProfile(id uint64) error {
+// SetProfileImage stores an uploaded avatar or cover image and replaces the
+// previous one. Images must be JPEG, PNG, GIF or WebP files of at most
+// maxImageSize bytes.
+func (u *userUsecase) SetProfileImage(id uint64, kind string, image io.Reader) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
-	// Delete from database
-	if err := u.userRepo.Delete(id); err != nil {
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	imageURL, column, err := profileImageURL(user, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte more than allowed to tell oversized images apart
+	data, err := io.ReadAll(io.LimitReader(image, u.maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > u.maxImageSize {
+		return nil, fmt.Errorf("image must not be larger than %d bytes", u.maxImageSize)
+	}
+	ext, ok := profileImageTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, errors.New("image must be a JPEG, PNG, GIF or WebP file")
+	}
+
+	// Every upload gets a new name so that cached copies of the old image
+	// are never served in its place
+	name := fmt.Sprintf("users/%d/%s-%d%s", id, kind, time.Now().UnixNano(), ext)
+	newURL, err := u.mediaStore.Put(name, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	oldURL := *imageURL
+	*imageURL = newURL
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(user, column); err != nil {
+		if err := u.mediaStore.Delete(newURL); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		return nil, err
+	}
+
+	u.replacedProfileImage(ctx, user, oldURL)
+	return user, nil
+}
+
+// DeleteProfileImage removes the avatar or cover image of a user
+func (u *userUsecase) DeleteProfileImage(id uint64, kind string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	imageURL, column, err := profileImageURL(user, kind)
+	if err != nil {
+		return nil, err
+	}
+	if *imageURL == "" {
+		return user, nil
+	}
+
+	oldURL := *imageURL
+	*imageURL = ""
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(user, column); err != nil {
+		return nil, err
+	}
+
+	u.replacedProfileImage(ctx, user, oldURL)
+	return user, nil
+}
+
+// replacedProfileImage deletes a profile image no longer used and drops the
+// cached user
+func (u *userUsecase) replacedProfileImage(ctx context.Context, user *domain.User, oldURL string) {
+	if oldURL != "" {
+		if err := u.mediaStore.Delete(oldURL); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	if err := u.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+// profileImageURL returns the field and column holding the URL of a kind of
+// profile image
+func profileImageURL(user *domain.User, kind string) (*string, string, error) {
+	switch kind {
+	case domain.ProfileImageAvatar:
+		return &user.AvatarURL, "avatar_url", nil
+	case domain.ProfileImageCover:
+		return &user.CoverURL, "cover_url", nil
+	}
+	return nil, "", fmt.Errorf("unknown profile image %q", kind)
+}
+
+// SetPrivate makes an account private or public. Pending follow requests are
+// approved when the account becomes public.
+func (u *userUsecase) SetPrivate(id uint64, private bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	changed := user.IsPrivate != private
+	user.IsPrivate = private
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(user, "is_private"); err != nil {
 		return err
 	}
 
-	// Delete from cache
-	return u.userCache.DeleteUser(ctx, id)
+	if err := u.userCache.DeleteUser(ctx, id); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	// Only posts of public accounts can be shared, so reposts and quotes of
+	// the user's posts appear or disappear with the change
+	if changed {
+		shares, err := u.postRepo.GetSharesByAuthor(id)
+		if err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		} else {
+			invalidateShareCaches(ctx, u.postCache, u.userRepo, shares)
+		}
+	}
+
+	if private {
+		return nil
+	}
+
+	requesterIDs, err := u.followRequests.ApproveAll(id)
+	if err != nil {
+		return err
+	}
+	if len(requesterIDs) == 0 {
+		return nil
+	}
+
+	// Invalidate follow counts cache
+	for _, userID := range append(requesterIDs, id) {
+		if err := u.userCache.DeleteFollows(ctx, userID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	return nil
 }
 
-func (u *userUsecase) Follow(followerID, followingID uint64) error {
+// Follow follows a user, or sends a follow request if the account is private.
+// It returns domain.FollowStatusFollowing or domain.FollowStatusRequested.
+func (u *userUsecase) Follow(followerID, followingID uint64) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
+	// Blocked users cannot follow each other
+	blocked, err := u.blockRepo.IsBlocked(followerID, followingID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", errBlocked
+	}
+
+	target, err := u.GetProfile(followingID)
+	if err != nil {
+		return "", err
+	}
+
+	// Private accounts approve their followers
+	if target.IsPrivate {
+		following, err := u.userRepo.IsFollowing(followerID, followingID)
+		if err != nil {
+			return "", err
+		}
+		if following {
+			return domain.FollowStatusFollowing, nil
+		}
+
+		request := &domain.FollowRequest{
+			RequesterID: followerID,
+			TargetID:    followingID,
+			CreatedAt:   time.Now(),
+		}
+		if err := u.followRequests.Create(request); err != nil {
+			return "", err
+		}
+
+		u.webhooks.Dispatch(domain.EventFollowRequested, request, followingID, followerID)
+
+		return domain.FollowStatusRequested, nil
+	}
+
 	// Add follower in database
 	if err := u.userRepo.Follow(followerID, followingID); err != nil {
-		return err
+		return "", err
 	}
 
-	// Invalidate followers and following cache
+	// Invalidate follow counts cache
 	if err := u.userCache.DeleteUser(ctx, followerID); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
@@ -176,8 +683,17 @@ func (u *userUsecase) Follow(followerID, followingID uint64) error {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
+	for _, userID := range []uint64{followerID, followingID} {
+		if err := u.userCache.DeleteFollows(ctx, userID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
 
-	return nil
+	follow := &domain.Follow{FollowerID: followerID, FollowingID: followingID, CreatedAt: time.Now()}
+	u.webhooks.Dispatch(domain.EventFollowCreated, follow, followingID, followerID)
+
+	return domain.FollowStatusFollowing, nil
 }
 
 func (u *userUsecase) Unfollow(followerID, followingID uint64) error {
@@ -189,7 +705,12 @@ func (u *userUsecase) Unfollow(followerID, followingID uint64) error {
 		return err
 	}
 
-	// Invalidate followers and following cache
+	// Unfollowing also withdraws a pending follow request
+	if err := u.followRequests.Delete(followerID, followingID); err != nil {
+		return err
+	}
+
+	// Invalidate follow counts cache
 	if err := u.userCache.DeleteUser(ctx, followerID); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
@@ -198,56 +719,90 @@ func (u *userUsecase) Unfollow(followerID, followingID uint64) error {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
+	for _, userID := range []uint64{followerID, followingID} {
+		if err := u.userCache.DeleteFollows(ctx, userID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	}
+
+	follow := &domain.Follow{FollowerID: followerID, FollowingID: followingID}
+	u.webhooks.Dispatch(domain.EventFollowDeleted, follow, followingID, followerID)
 
 	return nil
 }
 
-func (u *userUsecase) GetFollowers(userID uint64) ([]domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
-	defer cancel()
+// GetFollowers returns a page of the followers of a user. Rows are flagged
+// with the relationship to the viewer, so pages are not cached.
+func (u *userUsecase) GetFollowers(userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error) {
+	if err := u.checkAccountVisible(userID, viewerID); err != nil {
+		return nil, err
+	}
 
-	// Try to get from cache first
-	followers, err := u.userCache.GetFollowers(ctx, userID)
-	if err == nil {
-		return followers, nil
+	return u.userRepo.GetFollowersPage(userID, viewerID, page, limit)
+}
+
+// GetFollowing returns a page of the users a user follows, flagged like GetFollowers
+func (u *userUsecase) GetFollowing(userID, viewerID uint64, page, limit int) ([]domain.FollowListEntry, error) {
+	if err := u.checkAccountVisible(userID, viewerID); err != nil {
+		return nil, err
 	}
 
-	// If not in cache, get from database
-	followers, err = u.userRepo.GetFollowers(userID)
+	return u.userRepo.GetFollowingPage(userID, viewerID, page, limit)
+}
+
+// GetProfileStats returns the number of posts, followers and followed
+// accounts of a user. Stats are public, also for private accounts.
+func (u *userUsecase) GetProfileStats(userID uint64) (*domain.ProfileStats, error) {
+	counts, err := u.getFollowCounts(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache the followers
-	if err := u.userCache.SetFollowers(ctx, userID, followers); err != nil {
-		// Log error but don't return it
-		// TODO: Add proper logging
+	posts, err := u.userRepo.CountPosts(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return followers, nil
+	return &domain.ProfileStats{
+		Posts:     posts,
+		Followers: counts.Followers,
+		Following: counts.Following,
+	}, nil
 }
 
-func (u *userUsecase) GetFollowing(userID uint64) ([]domain.User, error) {
+// getFollowCounts returns the cached follow counts of a user
+func (u *userUsecase) getFollowCounts(userID uint64) (*domain.FollowCounts, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
 	defer cancel()
 
 	// Try to get from cache first
-	following, err := u.userCache.GetFollowing(ctx, userID)
+	counts, err := u.userCache.GetFollowCounts(ctx, userID)
 	if err == nil {
-		return following, nil
+		return counts, nil
 	}
 
 	// If not in cache, get from database
-	following, err = u.userRepo.GetFollowing(userID)
+	counts, err = u.userRepo.CountFollows(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache the following
-	if err := u.userCache.SetFollowing(ctx, userID, following); err != nil {
+	// Cache the counts
+	if err := u.userCache.SetFollowCounts(ctx, userID, counts); err != nil {
 		// Log error but don't return it
 		// TODO: Add proper logging
 	}
 
-	return following, nil
+	return counts, nil
+}
+
+// checkAccountVisible returns an error unless viewerID may see the follows of userID
+func (u *userUsecase) checkAccountVisible(userID, viewerID uint64) error {
+	owner, err := u.GetProfile(userID)
+	if err != nil {
+		return err
+	}
+
+	return checkAccountVisible(u.userRepo, u.blockRepo, viewerID, owner)
 }
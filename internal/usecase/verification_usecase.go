@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/repository/cache"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errInvalidToken = errors.New("invalid or expired token")
+
+type verificationUsecase struct {
+	tokenRepo      domain.VerificationTokenRepository
+	userRepo       domain.UserRepository
+	userCache      cache.UserCache
+	twoFactor      domain.TwoFactorUsecase
+	mailer         mailer.Mailer
+	emailTokenTTL  time.Duration
+	resetTokenTTL  time.Duration
+	linkBaseURL    string
+	contextTimeout time.Duration
+}
+
+// NewVerificationUsecase creates a new verification usecase. Links in emails
+// are built from linkBaseURL.
+func NewVerificationUsecase(
+	tr domain.VerificationTokenRepository,
+	ur domain.UserRepository,
+	uc cache.UserCache,
+	tfu domain.TwoFactorUsecase,
+	m mailer.Mailer,
+	emailTokenTTL time.Duration,
+	resetTokenTTL time.Duration,
+	linkBaseURL string,
+	timeout time.Duration,
+) domain.VerificationUsecase {
+	return &verificationUsecase{
+		tokenRepo:      tr,
+		userRepo:       ur,
+		userCache:      uc,
+		twoFactor:      tfu,
+		mailer:         m,
+		emailTokenTTL:  emailTokenTTL,
+		resetTokenTTL:  resetTokenTTL,
+		linkBaseURL:    linkBaseURL,
+		contextTimeout: timeout,
+	}
+}
+
+func (v *verificationUsecase) SendEmailVerification(userID uint64) error {
+	user, err := v.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if user.EmailVerified {
+		return errors.New("email already verified")
+	}
+
+	token, err := v.issueToken(user.ID, domain.TokenPurposeEmailVerification, "", v.emailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return v.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s/verify-email?token=%s\n\nThis link expires in %s.\n",
+			user.FirstName, v.linkBaseURL, token, v.emailTokenTTL,
+		),
+	})
+}
+
+func (v *verificationUsecase) VerifyEmail(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.contextTimeout)
+	defer cancel()
+
+	verificationToken, err := v.consumeToken(domain.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := v.userRepo.GetByID(verificationToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errInvalidToken
+	}
+
+	// Mark email as verified
+	now := time.Now()
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := v.userRepo.Update(user, "email_verified", "email_verified_at"); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := v.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// ForgotPassword emails a password reset link. It does not report whether the
+// email belongs to an account.
+func (v *verificationUsecase) ForgotPassword(email string) error {
+	user, err := v.userRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := v.issueToken(user.ID, domain.TokenPurposePasswordReset, "", v.resetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return v.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s/reset-password?token=%s\n\nThis link expires in %s. If you did not request a reset you can ignore this email.\n",
+			user.FirstName, v.linkBaseURL, token, v.resetTokenTTL,
+		),
+	})
+}
+
+func (v *verificationUsecase) ResetPassword(token, password string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.contextTimeout)
+	defer cancel()
+
+	resetToken, err := v.consumeToken(domain.TokenPurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := v.userRepo.GetByID(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errInvalidToken
+	}
+
+	// Hash password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+
+	if err := v.userRepo.Update(user, "password"); err != nil {
+		return err
+	}
+
+	// Any other outstanding reset links are no longer valid
+	if err := v.tokenRepo.InvalidateForUser(user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := v.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// RequestEmailChange emails a confirmation link to newEmail once the user
+// confirmed their password, and a 2FA code when enabled. The current address
+// is kept until the link is opened, and is told about the request.
+func (v *verificationUsecase) RequestEmailChange(userID uint64, newEmail, password, code string) error {
+	user, err := v.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if err := confirmIdentity(v.twoFactor, user, password, code); err != nil {
+		return err
+	}
+	if user.Email == newEmail {
+		return errors.New("email is unchanged")
+	}
+
+	existingUser, err := v.userRepo.GetByEmail(newEmail)
+	if err != nil {
+		return err
+	}
+	if existingUser != nil {
+		return errors.New("email already exists")
+	}
+
+	token, err := v.issueToken(user.ID, domain.TokenPurposeEmailChange, newEmail, v.emailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	err = v.mailer.Send(&mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm that you want to use this email address for your account by opening the link below:\n\n%s/confirm-email?token=%s\n\nThis link expires in %s.\n",
+			user.FirstName, v.linkBaseURL, token, v.emailTokenTTL,
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := v.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to change the email address of your account to %s. It will only change once the new address is confirmed. If you did not request this, change your password.\n",
+			user.FirstName, newEmail,
+		),
+	}); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange replaces the email of a user with the address the token
+// was sent to, which is verified by opening the link
+func (v *verificationUsecase) ConfirmEmailChange(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.contextTimeout)
+	defer cancel()
+
+	changeToken, err := v.consumeToken(domain.TokenPurposeEmailChange, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := v.userRepo.GetByID(changeToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errInvalidToken
+	}
+
+	// The address may have been taken since the link was sent
+	existingUser, err := v.userRepo.GetByEmail(changeToken.NewEmail)
+	if err != nil {
+		return err
+	}
+	if existingUser != nil {
+		return errors.New("email already exists")
+	}
+
+	oldEmail := user.Email
+	now := time.Now()
+	user.Email = changeToken.NewEmail
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := v.userRepo.Update(user, "email", "email_verified", "email_verified_at"); err != nil {
+		return err
+	}
+
+	// Invalidate user cache
+	if err := v.userCache.DeleteUser(ctx, user.ID); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	if err := v.mailer.Send(&mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email address of your account was changed to %s. If you did not make this change, contact support.\n",
+			user.FirstName, user.Email,
+		),
+	}); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+
+	return nil
+}
+
+// issueToken replaces any outstanding token of the same purpose with a new one
+// and returns the plain token to send to the user. newEmail is only set for
+// email change tokens.
+func (v *verificationUsecase) issueToken(userID uint64, purpose, newEmail string, ttl time.Duration) (string, error) {
+	if err := v.tokenRepo.InvalidateForUser(userID, purpose); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	verificationToken := &domain.VerificationToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		NewEmail:  newEmail,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := v.tokenRepo.Create(verificationToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeToken validates a plain token and marks it as used
+func (v *verificationUsecase) consumeToken(purpose, token string) (*domain.VerificationToken, error) {
+	verificationToken, err := v.tokenRepo.GetByHash(purpose, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if verificationToken == nil || verificationToken.UsedAt != nil || time.Now().After(verificationToken.ExpiresAt) {
+		return nil, errInvalidToken
+	}
+
+	// Mark as used first so concurrent requests cannot reuse the token
+	used, err := v.tokenRepo.MarkUsed(verificationToken.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errInvalidToken
+	}
+
+	return verificationToken, nil
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/webhook"
+)
+
+type webhookUsecase struct {
+	webhookRepo    domain.WebhookRepository
+	sender         *webhook.Sender
+	maxFailures    int
+	contextTimeout time.Duration
+}
+
+// webhookPayload is the JSON envelope posted to receivers
+type webhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewWebhookUsecase creates a new webhook usecase. Webhooks are disabled after
+// maxFailures consecutive failed deliveries.
+func NewWebhookUsecase(wr domain.WebhookRepository, sender *webhook.Sender, maxFailures int, timeout time.Duration) domain.WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo:    wr,
+		sender:         sender,
+		maxFailures:    maxFailures,
+		contextTimeout: timeout,
+	}
+}
+
+func (w *webhookUsecase) CreateWebhook(hook *domain.Webhook) error {
+	if err := w.validateWebhook(hook); err != nil {
+		return err
+	}
+	if hook.Secret == "" {
+		return errors.New("webhook secret is required")
+	}
+
+	// Set defaults and timestamps
+	now := time.Now()
+	hook.Active = true
+	hook.ConsecutiveFailures = 0
+	hook.CreatedAt = now
+	hook.UpdatedAt = now
+
+	return w.webhookRepo.Create(hook)
+}
+
+func (w *webhookUsecase) GetWebhook(id, userID uint64) (*domain.Webhook, error) {
+	hook, err := w.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return nil, errors.New("webhook not found")
+	}
+	if hook.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return hook, nil
+}
+
+func (w *webhookUsecase) GetUserWebhooks(userID uint64) ([]domain.Webhook, error) {
+	return w.webhookRepo.GetByUserID(userID)
+}
+
+func (w *webhookUsecase) UpdateWebhook(hook *domain.Webhook) error {
+	// Verify webhook exists and belongs to user
+	existingHook, err := w.GetWebhook(hook.ID, hook.UserID)
+	if err != nil {
+		return err
+	}
+	if err := w.validateWebhook(hook); err != nil {
+		return err
+	}
+
+	// Re-enabling a webhook gives it a fresh failure budget
+	if hook.Active && !existingHook.Active {
+		hook.ConsecutiveFailures = 0
+		hook.DisabledAt = nil
+	}
+	if !hook.Active && existingHook.Active {
+		now := time.Now()
+		hook.DisabledAt = &now
+	}
+
+	hook.CreatedAt = existingHook.CreatedAt
+	hook.UpdatedAt = time.Now()
+
+	return w.webhookRepo.Update(hook)
+}
+
+func (w *webhookUsecase) DeleteWebhook(id, userID uint64) error {
+	// Verify webhook exists and belongs to user
+	if _, err := w.GetWebhook(id, userID); err != nil {
+		return err
+	}
+
+	return w.webhookRepo.Delete(id)
+}
+
+func (w *webhookUsecase) GetDeliveries(webhookID, userID uint64, page, limit int) ([]domain.WebhookDelivery, error) {
+	// Verify webhook exists and belongs to user
+	if _, err := w.GetWebhook(webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepo.GetDeliveries(webhookID, page, limit)
+}
+
+func (w *webhookUsecase) Redeliver(webhookID, deliveryID, userID uint64) (*domain.WebhookDelivery, error) {
+	// Verify webhook exists and belongs to user
+	hook, err := w.GetWebhook(webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := w.webhookRepo.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.WebhookID != hook.ID {
+		return nil, errors.New("delivery not found")
+	}
+
+	// Redeliveries get their own log entry pointing at the original
+	delivery := &domain.WebhookDelivery{
+		WebhookID:    hook.ID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := w.webhookRepo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	go w.deliver(hook, delivery)
+
+	return delivery, nil
+}
+
+// Dispatch delivers event to the active webhooks of the given users that are
+// subscribed to it. Deliveries happen in the background and never block the caller.
+func (w *webhookUsecase) Dispatch(event string, data interface{}, userIDs ...uint64) {
+	// Deduplicate recipients so a user acting on their own content gets one delivery
+	seen := make(map[uint64]bool, len(userIDs))
+	recipients := make([]uint64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipients = append(recipients, id)
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+		return
+	}
+
+	go func() {
+		hooks, err := w.webhookRepo.GetActiveByUserIDs(recipients)
+		if err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+			return
+		}
+
+		for i := range hooks {
+			hook := hooks[i]
+			if !hook.Subscribes(event) {
+				continue
+			}
+
+			delivery := &domain.WebhookDelivery{
+				WebhookID: hook.ID,
+				Event:     event,
+				Payload:   string(payload),
+			}
+			if err := w.webhookRepo.CreateDelivery(delivery); err != nil {
+				// Log error but don't return it
+				// TODO: Add proper logging
+				continue
+			}
+
+			go w.deliver(&hook, delivery)
+		}
+	}()
+}
+
+// deliver sends a delivery with retries, logging every attempt and tracking
+// consecutive failures on the webhook
+func (w *webhookUsecase) deliver(hook *domain.Webhook, delivery *domain.WebhookDelivery) {
+	req := &webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       []byte(delivery.Payload),
+	}
+
+	result := w.sender.Deliver(context.Background(), req, func(res *webhook.Result) {
+		delivery.Attempts = res.Attempt
+		delivery.StatusCode = res.StatusCode
+		delivery.ResponseBody = res.ResponseBody
+		delivery.Success = res.Success()
+		delivery.Error = ""
+		if res.Err != nil {
+			delivery.Error = res.Err.Error()
+		}
+		if delivery.Success {
+			now := time.Now()
+			delivery.DeliveredAt = &now
+		}
+
+		if err := w.webhookRepo.UpdateDelivery(delivery); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+	})
+
+	if result.Success() {
+		if err := w.webhookRepo.ResetFailures(hook.ID); err != nil {
+			// Log error but don't return it
+			// TODO: Add proper logging
+		}
+		return
+	}
+
+	// Disable the webhook after too many consecutive failed deliveries
+	if _, err := w.webhookRepo.RecordFailure(hook.ID, w.maxFailures); err != nil {
+		// Log error but don't return it
+		// TODO: Add proper logging
+	}
+}
+
+// validateWebhook checks the target URL and subscribed events. Receivers
+// must be https URLs on public addresses.
+func (w *webhookUsecase) validateWebhook(hook *domain.Webhook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.contextTimeout)
+	defer cancel()
+
+	if err := w.sender.ValidateURL(ctx, hook.URL); err != nil {
+		return err
+	}
+
+	events := hook.EventList()
+	if len(events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	for _, event := range events {
+		if !domain.IsValidWebhookEvent(event) {
+			return errors.New("unknown webhook event: " + event)
+		}
+	}
+
+	return nil
+}
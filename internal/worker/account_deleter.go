@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// AccountDeleter periodically runs queued account deletions. Each deletion is
+// claimed by a single instance and saved after every batch, so a deletion
+// interrupted by a restart resumes where it stopped.
+type AccountDeleter struct {
+	deletionUsecase domain.AccountDeletionUsecase
+	logger          *logrus.Logger
+	interval        time.Duration
+	batchSize       int
+}
+
+// NewAccountDeleter creates a deleter polling every interval and deleting at
+// most batchSize rows of each kind per batch
+func NewAccountDeleter(deletionUsecase domain.AccountDeletionUsecase, logger *logrus.Logger, interval time.Duration, batchSize int) *AccountDeleter {
+	return &AccountDeleter{
+		deletionUsecase: deletionUsecase,
+		logger:          logger,
+		interval:        interval,
+		batchSize:       batchSize,
+	}
+}
+
+// Run processes queued account deletions until ctx is cancelled
+func (d *AccountDeleter) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.logger.Infof("Account deleter is running every %s", d.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Account deleter stopped")
+			return
+		case <-ticker.C:
+			d.process(ctx)
+		}
+	}
+}
+
+// process runs queued deletions one after another until none is left
+func (d *AccountDeleter) process(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := d.deletionUsecase.ProcessNext(d.batchSize)
+		if err != nil {
+			d.logger.Errorf("Failed to delete account: %v", err)
+			return
+		}
+		if !processed {
+			return
+		}
+		d.logger.Info("Deleted an account")
+	}
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// DataExporter periodically builds queued data exports and deletes the ones
+// whose download expired. Each export is claimed by a single instance.
+type DataExporter struct {
+	exportUsecase domain.DataExportUsecase
+	logger        *logrus.Logger
+	interval      time.Duration
+	batchSize     int
+}
+
+// NewDataExporter creates an exporter polling every interval and loading at
+// most batchSize rows of each kind per query
+func NewDataExporter(exportUsecase domain.DataExportUsecase, logger *logrus.Logger, interval time.Duration, batchSize int) *DataExporter {
+	return &DataExporter{
+		exportUsecase: exportUsecase,
+		logger:        logger,
+		interval:      interval,
+		batchSize:     batchSize,
+	}
+}
+
+// Run processes queued exports until ctx is cancelled
+func (e *DataExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.logger.Infof("Data exporter is running every %s", e.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Data exporter stopped")
+			return
+		case <-ticker.C:
+			e.process(ctx)
+			e.deleteExpired()
+		}
+	}
+}
+
+// process builds queued exports one after another until none is left
+func (e *DataExporter) process(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := e.exportUsecase.ProcessNext(e.batchSize)
+		if err != nil {
+			e.logger.Errorf("Failed to export data: %v", err)
+			return
+		}
+		if !processed {
+			return
+		}
+		e.logger.Info("Exported user data")
+	}
+}
+
+// deleteExpired deletes the archives whose download link expired
+func (e *DataExporter) deleteExpired() {
+	deleted, err := e.exportUsecase.DeleteExpired(e.batchSize)
+	if err != nil {
+		e.logger.Errorf("Failed to delete expired data exports: %v", err)
+		return
+	}
+	if deleted > 0 {
+		e.logger.Infof("Deleted %d expired data exports", deleted)
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Importer periodically runs queued imports. Each import is claimed by a
+// single instance and checkpointed after every batch.
+type Importer struct {
+	importUsecase domain.ImportUsecase
+	logger        *logrus.Logger
+	interval      time.Duration
+}
+
+// NewImporter creates an importer polling every interval
+func NewImporter(importUsecase domain.ImportUsecase, logger *logrus.Logger, interval time.Duration) *Importer {
+	return &Importer{
+		importUsecase: importUsecase,
+		logger:        logger,
+		interval:      interval,
+	}
+}
+
+// Run processes queued imports until ctx is cancelled
+func (i *Importer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	i.logger.Infof("Importer is running every %s", i.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			i.logger.Info("Importer stopped")
+			return
+		case <-ticker.C:
+			i.process(ctx)
+		}
+	}
+}
+
+// process runs queued imports one after another until none is left
+func (i *Importer) process(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := i.importUsecase.ProcessNext()
+		if err != nil {
+			i.logger.Errorf("Failed to import: %v", err)
+			return
+		}
+		if !processed {
+			return
+		}
+		i.logger.Info("Finished an import")
+	}
+}
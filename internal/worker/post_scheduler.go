@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// PostScheduler periodically publishes scheduled posts that are due. Several
+// instances may run at once, each post is still published exactly once.
+type PostScheduler struct {
+	postUsecase domain.PostUsecase
+	logger      *logrus.Logger
+	interval    time.Duration
+	batchSize   int
+}
+
+// NewPostScheduler creates a scheduler polling every interval and publishing
+// at most batchSize posts per query
+func NewPostScheduler(postUsecase domain.PostUsecase, logger *logrus.Logger, interval time.Duration, batchSize int) *PostScheduler {
+	return &PostScheduler{
+		postUsecase: postUsecase,
+		logger:      logger,
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Run publishes due posts until ctx is cancelled
+func (s *PostScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Post scheduler is running every %s", s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Post scheduler stopped")
+			return
+		case <-ticker.C:
+			s.publishDue(ctx)
+		}
+	}
+}
+
+// publishDue drains the due posts batch by batch
+func (s *PostScheduler) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := s.postUsecase.PublishDuePosts(s.batchSize)
+		if err != nil {
+			s.logger.Errorf("Failed to publish scheduled posts: %v", err)
+			return
+		}
+		if published > 0 {
+			s.logger.Infof("Published %d scheduled posts", published)
+		}
+		if published < s.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Purger periodically removes soft deleted rows whose restore window has
+// passed. Running it on several instances only repeats idempotent deletes.
+type Purger struct {
+	purgeUsecase domain.PurgeUsecase
+	logger       *logrus.Logger
+	interval     time.Duration
+	batchSize    int
+}
+
+// NewPurger creates a purger running every interval and purging at most
+// batchSize rows of each kind per pass
+func NewPurger(purgeUsecase domain.PurgeUsecase, logger *logrus.Logger, interval time.Duration, batchSize int) *Purger {
+	return &Purger{
+		purgeUsecase: purgeUsecase,
+		logger:       logger,
+		interval:     interval,
+		batchSize:    batchSize,
+	}
+}
+
+// Run purges expired rows until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Infof("Purger is running every %s", p.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Purger stopped")
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+// purge drains the expired rows pass by pass
+func (p *Purger) purge(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := p.purgeUsecase.PurgeExpired(p.batchSize)
+		if err != nil {
+			p.logger.Errorf("Failed to purge deleted rows: %v", err)
+			return
+		}
+		if purged > 0 {
+			p.logger.Infof("Purged %d deleted rows", purged)
+		}
+		if purged < p.batchSize {
+			return
+		}
+	}
+}
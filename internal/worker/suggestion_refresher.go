@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// SuggestionRefresher periodically recomputes the follow suggestions of every
+// user. Each instance running it repeats the whole pass, so enable it on one.
+type SuggestionRefresher struct {
+	suggestionUsecase domain.SuggestionUsecase
+	logger            *logrus.Logger
+	interval          time.Duration
+	batchSize         int
+}
+
+// NewSuggestionRefresher creates a refresher running every interval and
+// loading batchSize users at a time
+func NewSuggestionRefresher(suggestionUsecase domain.SuggestionUsecase, logger *logrus.Logger, interval time.Duration, batchSize int) *SuggestionRefresher {
+	return &SuggestionRefresher{
+		suggestionUsecase: suggestionUsecase,
+		logger:            logger,
+		interval:          interval,
+		batchSize:         batchSize,
+	}
+}
+
+// Run refreshes suggestions until ctx is cancelled
+func (r *SuggestionRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Infof("Suggestion refresher is running every %s", r.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Suggestion refresher stopped")
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// refresh goes through all users batch by batch
+func (r *SuggestionRefresher) refresh(ctx context.Context) {
+	var afterID uint64
+	total := 0
+	for ctx.Err() == nil {
+		lastID, refreshed, err := r.suggestionUsecase.RefreshBatch(afterID, r.batchSize)
+		total += refreshed
+		if err != nil {
+			r.logger.Errorf("Failed to refresh suggestions after user %d: %v", lastID, err)
+			return
+		}
+		if refreshed < r.batchSize {
+			break
+		}
+		afterID = lastID
+	}
+	r.logger.Infof("Refreshed suggestions of %d users", total)
+}
@@ -47,6 +47,11 @@ func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, key).Result()
 }
 
+// GetDel retrieves a value by key and removes it atomically
+func (r *RedisClient) GetDel(ctx context.Context, key string) (string, error) {
+	return r.client.GetDel(ctx, key).Result()
+}
+
 // Delete removes a key
 func (r *RedisClient) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
@@ -64,6 +69,58 @@ func (r *RedisClient) DeletePattern(ctx context.Context, pattern string) error {
 	return iter.Err()
 }
 
+// incrScript increments a counter and sets its expiration, in milliseconds,
+// when it has none. Running both in one script means a counter can never be
+// left without an expiration.
+var incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Incr increments a counter, setting its expiration when it is first created
+func (r *RedisClient) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrScript.Run(ctx, r.client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+// Version returns the value of a version counter, or zero if it does not exist
+func (r *RedisClient) Version(ctx context.Context, key string) (int64, error) {
+	version, err := r.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// BumpVersions increments version counters in one round trip and extends
+// their expiration
+func (r *RedisClient) BumpVersions(ctx context.Context, keys []string, expiration time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, key := range keys {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// TTL returns the remaining time to live of a key, or zero if it has none
+func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
 // SetHash stores a hash map with expiration
 func (r *RedisClient) SetHash(ctx context.Context, key string, values map[string]interface{}, expiration time.Duration) error {
 	pipe := r.client.Pipeline()
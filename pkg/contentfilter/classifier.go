@@ -0,0 +1,75 @@
+package contentfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClassifierFilter asks an external HTTP service for a decision. The service
+// receives a JSON body {"user_id", "kind", "content"} and answers with
+// {"decision": "allow|flag|reject", "reason": "..."}.
+type ClassifierFilter struct {
+	url    string
+	client *http.Client
+}
+
+type classifierRequest struct {
+	UserID  uint64 `json:"user_id"`
+	Kind    string `json:"kind"`
+	Content string `json:"content"`
+}
+
+type classifierResponse struct {
+	Decision Decision `json:"decision"`
+	Reason   string   `json:"reason"`
+}
+
+// NewClassifierFilter creates a new external classifier filter
+func NewClassifierFilter(url string, timeout time.Duration) *ClassifierFilter {
+	return &ClassifierFilter{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (f *ClassifierFilter) Name() string {
+	return "classifier"
+}
+
+func (f *ClassifierFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	body, err := json.Marshal(classifierRequest{UserID: s.UserID, Kind: s.Kind, Content: s.Content})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("classifier request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("classifier returned status code %d", resp.StatusCode)
+	}
+
+	var result classifierResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return Result{}, fmt.Errorf("invalid classifier response: %v", err)
+	}
+	if _, ok := severity[result.Decision]; !ok {
+		return Result{}, fmt.Errorf("invalid classifier decision: %s", result.Decision)
+	}
+
+	return Result{Decision: result.Decision, Reason: result.Reason}, nil
+}
@@ -0,0 +1,45 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DuplicateCounter counts the recent submissions of a user with identical content
+type DuplicateCounter interface {
+	CountRecentDuplicates(userID uint64, kind, content string, since time.Time) (int64, error)
+}
+
+// DuplicateFilter flags users posting the same content over and over
+type DuplicateFilter struct {
+	counter       DuplicateCounter
+	window        time.Duration
+	maxDuplicates int64
+}
+
+// NewDuplicateFilter creates a new duplicate filter. A submission is flagged
+// when the user already wrote the same content maxDuplicates times in window.
+func NewDuplicateFilter(counter DuplicateCounter, window time.Duration, maxDuplicates int) *DuplicateFilter {
+	return &DuplicateFilter{counter: counter, window: window, maxDuplicates: int64(maxDuplicates)}
+}
+
+func (f *DuplicateFilter) Name() string {
+	return "duplicates"
+}
+
+func (f *DuplicateFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	if f.maxDuplicates <= 0 {
+		return Result{Decision: Allow}, nil
+	}
+
+	count, err := f.counter.CountRecentDuplicates(s.UserID, s.Kind, s.Content, time.Now().Add(-f.window))
+	if err != nil {
+		return Result{}, err
+	}
+	if count >= f.maxDuplicates {
+		return Result{Decision: Flag, Reason: fmt.Sprintf("same content written %d times in %s", count, f.window)}, nil
+	}
+
+	return Result{Decision: Allow}, nil
+}
@@ -0,0 +1,57 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeDuplicateCounter struct {
+	count int64
+	err   error
+	since time.Time
+}
+
+func (c *fakeDuplicateCounter) CountRecentDuplicates(userID uint64, kind, content string, since time.Time) (int64, error) {
+	c.since = since
+	return c.count, c.err
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         int64
+		maxDuplicates int
+		want          Decision
+	}{
+		{"first time", 0, 3, Allow},
+		{"below the limit", 2, 3, Allow},
+		{"at the limit", 3, 3, Flag},
+		{"disabled", 10, 0, Allow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &fakeDuplicateCounter{count: tt.count}
+			filter := NewDuplicateFilter(counter, time.Hour, tt.maxDuplicates)
+
+			result, err := filter.Check(context.Background(), &Submission{UserID: 1, Kind: "post", Content: "spam"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Decision != tt.want {
+				t.Errorf("Check() = %s, want %s", result.Decision, tt.want)
+			}
+			if tt.maxDuplicates > 0 && time.Since(counter.since) < time.Hour-time.Minute {
+				t.Errorf("counted since %s, want the last hour", counter.since)
+			}
+		})
+	}
+}
+
+func TestDuplicateFilterError(t *testing.T) {
+	filter := NewDuplicateFilter(&fakeDuplicateCounter{err: errors.New("db down")}, time.Hour, 3)
+	if _, err := filter.Check(context.Background(), &Submission{Content: "spam"}); err == nil {
+		t.Error("Check() swallowed the counter error")
+	}
+}
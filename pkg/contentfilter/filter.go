@@ -0,0 +1,94 @@
+package contentfilter
+
+import (
+	"context"
+)
+
+// Decision is the outcome of a filter, ordered by severity
+type Decision string
+
+const (
+	Allow  Decision = "allow"
+	Flag   Decision = "flag"
+	Reject Decision = "reject"
+)
+
+// severity orders decisions so the most severe one wins
+var severity = map[Decision]int{
+	Allow:  0,
+	Flag:   1,
+	Reject: 2,
+}
+
+// Submission is content about to be written
+type Submission struct {
+	UserID  uint64
+	Kind    string
+	Content string
+}
+
+// Result is the decision of a single filter
+type Result struct {
+	Filter   string
+	Decision Decision
+	Reason   string
+}
+
+// Filter inspects a submission. Filters return Allow with an empty reason
+// when they have no objection.
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, s *Submission) (Result, error)
+}
+
+// Chain runs filters in order
+type Chain struct {
+	filters []Filter
+}
+
+// NewChain creates a new filter chain
+func NewChain(filters ...Filter) *Chain {
+	return &Chain{filters: filters}
+}
+
+// Run returns the most severe decision and the results of all filters that did
+// not allow the submission. It stops at the first rejection. Filters that fail
+// are skipped so that an outage of e.g. an external classifier does not block
+// writes; their errors are returned alongside the decision.
+func (c *Chain) Run(ctx context.Context, s *Submission) (Decision, []Result, []error) {
+	decision := Allow
+	var results []Result
+	var errs []error
+
+	for _, filter := range c.filters {
+		result, err := filter.Check(ctx, s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if result.Decision == Allow {
+			continue
+		}
+
+		result.Filter = filter.Name()
+		results = append(results, result)
+		if severity[result.Decision] > severity[decision] {
+			decision = result.Decision
+		}
+		if decision == Reject {
+			break
+		}
+	}
+
+	return decision, results, errs
+}
+
+// ParseDecision converts a configured decision, defaulting to Reject
+func ParseDecision(value string) Decision {
+	switch Decision(value) {
+	case Allow, Flag:
+		return Decision(value)
+	default:
+		return Reject
+	}
+}
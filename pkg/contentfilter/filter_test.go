@@ -0,0 +1,82 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// stubFilter returns a fixed result and records whether it ran
+type stubFilter struct {
+	name   string
+	result Result
+	err    error
+	ran    bool
+}
+
+func (f *stubFilter) Name() string {
+	return f.name
+}
+
+func (f *stubFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	f.ran = true
+	return f.result, f.err
+}
+
+func TestChainRun(t *testing.T) {
+	allow := &stubFilter{name: "allow", result: Result{Decision: Allow}}
+	flag := &stubFilter{name: "flag", result: Result{Decision: Flag, Reason: "suspicious"}}
+	failing := &stubFilter{name: "failing", err: errors.New("unreachable")}
+	reject := &stubFilter{name: "reject", result: Result{Decision: Reject, Reason: "banned"}}
+	after := &stubFilter{name: "after", result: Result{Decision: Flag}}
+
+	decision, results, errs := NewChain(allow, flag, failing, reject, after).Run(context.Background(), &Submission{Content: "x"})
+
+	if decision != Reject {
+		t.Errorf("decision = %s, want reject", decision)
+	}
+	want := []Result{
+		{Filter: "flag", Decision: Flag, Reason: "suspicious"},
+		{Filter: "reject", Decision: Reject, Reason: "banned"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %+v, want %+v", results, want)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want the failing filter's", len(errs))
+	}
+	if after.ran {
+		t.Error("the chain kept running after a rejection")
+	}
+}
+
+func TestChainRunKeepsMostSevereDecision(t *testing.T) {
+	flag := &stubFilter{name: "flag", result: Result{Decision: Flag}}
+	allow := &stubFilter{name: "allow", result: Result{Decision: Allow}}
+
+	decision, results, errs := NewChain(flag, allow).Run(context.Background(), &Submission{})
+	if decision != Flag || len(results) != 1 || len(errs) != 0 {
+		t.Errorf("Run() = %s, %+v, %v, want a single flag", decision, results, errs)
+	}
+
+	decision, results, _ = NewChain().Run(context.Background(), &Submission{})
+	if decision != Allow || results != nil {
+		t.Errorf("empty chain Run() = %s, %+v, want allow", decision, results)
+	}
+}
+
+func TestParseDecision(t *testing.T) {
+	tests := map[string]Decision{
+		"allow":  Allow,
+		"flag":   Flag,
+		"reject": Reject,
+		"":       Reject,
+		"FLAG":   Reject,
+	}
+	for value, want := range tests {
+		if got := ParseDecision(value); got != want {
+			t.Errorf("ParseDecision(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// linkPattern finds http(s) URLs and bare www. links
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// LinkDomainFilter rejects links to blocked domains and their subdomains
+type LinkDomainFilter struct {
+	domains  []string
+	decision Decision
+}
+
+// NewLinkDomainFilter creates a new link domain filter returning decision on a match
+func NewLinkDomainFilter(domains []string, decision Decision) *LinkDomainFilter {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+	return &LinkDomainFilter{domains: normalized, decision: decision}
+}
+
+func (f *LinkDomainFilter) Name() string {
+	return "link_domains"
+}
+
+func (f *LinkDomainFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	for _, host := range linkHosts(s.Content) {
+		for _, domain := range f.domains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return Result{Decision: f.decision, Reason: "links to blocked domain: " + domain}, nil
+			}
+		}
+	}
+	return Result{Decision: Allow}, nil
+}
+
+// LinkDensityFilter flags content that is mostly links
+type LinkDensityFilter struct {
+	maxLinks int
+	maxRatio float64
+}
+
+// NewLinkDensityFilter creates a new link density filter. Content with more
+// than maxLinks links, or with more than maxRatio of its words being links, is
+// flagged. Zero disables the respective check.
+func NewLinkDensityFilter(maxLinks int, maxRatio float64) *LinkDensityFilter {
+	return &LinkDensityFilter{maxLinks: maxLinks, maxRatio: maxRatio}
+}
+
+func (f *LinkDensityFilter) Name() string {
+	return "link_density"
+}
+
+func (f *LinkDensityFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	links := len(linkPattern.FindAllString(s.Content, -1))
+	if links == 0 {
+		return Result{Decision: Allow}, nil
+	}
+
+	if f.maxLinks > 0 && links > f.maxLinks {
+		return Result{Decision: Flag, Reason: fmt.Sprintf("too many links: %d", links)}, nil
+	}
+
+	words := len(strings.Fields(s.Content))
+	if f.maxRatio > 0 && float64(links)/float64(words) > f.maxRatio {
+		return Result{Decision: Flag, Reason: fmt.Sprintf("%d of %d words are links", links, words)}, nil
+	}
+
+	return Result{Decision: Allow}, nil
+}
+
+// linkTrailers are punctuation characters ending a sentence or bracket
+// rather than a link
+const linkTrailers = ".,;:!?)]}"
+
+// linkHosts returns the lowercased hosts of all links in text, without the
+// trailing dot of fully qualified names
+func linkHosts(text string) []string {
+	var hosts []string
+	for _, link := range linkPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(link, linkTrailers)
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.TrimRight(strings.ToLower(u.Hostname()), ".")
+		if host == "" {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
@@ -0,0 +1,81 @@
+package contentfilter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLinkHosts(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"no links here", nil},
+		{"see https://Evil.com/path", []string{"evil.com"}},
+		{"see https://evil.com.", []string{"evil.com"}},
+		{"see https://evil.com./path", []string{"evil.com"}},
+		{"(www.evil.com), then", []string{"www.evil.com"}},
+		{"http://a.example.com:8080/x?y=1! and www.b.org;", []string{"a.example.com", "www.b.org"}},
+	}
+	for _, tt := range tests {
+		if got := linkHosts(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("linkHosts(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLinkDomainFilter(t *testing.T) {
+	filter := NewLinkDomainFilter([]string{" Evil.com. ", ""}, Reject)
+
+	tests := []struct {
+		content string
+		want    Decision
+	}{
+		{"nothing to see", Allow},
+		{"see https://evil.com", Reject},
+		{"see https://evil.com.", Reject},
+		{"see https://sub.evil.com/page", Reject},
+		{"see www.EVIL.com!", Reject},
+		{"see https://notevil.com", Allow},
+		{"see https://evil.com.example.org", Allow},
+		{"evil.com without a scheme", Allow},
+	}
+	for _, tt := range tests {
+		result, err := filter.Check(context.Background(), &Submission{Content: tt.content})
+		if err != nil {
+			t.Fatalf("Check(%q) error: %v", tt.content, err)
+		}
+		if result.Decision != tt.want {
+			t.Errorf("Check(%q) = %s, want %s", tt.content, result.Decision, tt.want)
+		}
+	}
+}
+
+func TestLinkDensityFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxLinks int
+		maxRatio float64
+		content  string
+		want     Decision
+	}{
+		{"no links", 2, 0.5, "just some words", Allow},
+		{"under both limits", 2, 0.5, "read https://a.com and tell me", Allow},
+		{"too many links", 2, 0, "https://a.com https://b.com https://c.com with words around them all", Flag},
+		{"mostly links", 0, 0.5, "https://a.com https://b.com hi", Flag},
+		{"limits disabled", 0, 0, "https://a.com https://b.com https://c.com", Allow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewLinkDensityFilter(tt.maxLinks, tt.maxRatio)
+			result, err := filter.Check(context.Background(), &Submission{Content: tt.content})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Decision != tt.want {
+				t.Errorf("Check() = %s (%s), want %s", result.Decision, result.Reason, tt.want)
+			}
+		})
+	}
+}
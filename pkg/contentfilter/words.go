@@ -0,0 +1,120 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// maxPhraseWords is the longest banned phrase that is matched
+const maxPhraseWords = 4
+
+// leetspeak maps common character substitutions back to letters
+var leetspeak = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'9': 'g',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'l',
+	'+': 't',
+}
+
+// BannedWordFilter matches words and phrases from a list after normalizing
+// case, leetspeak and stretched letters ("b4aaad" matches "bad"). Symbols such
+// as "!" are leetspeak and punctuation alike, so content is matched both with
+// and without the substitutions.
+type BannedWordFilter struct {
+	words    map[string]bool
+	decision Decision
+}
+
+// NewBannedWordFilter creates a new banned word filter returning decision on a match
+func NewBannedWordFilter(words []string, decision Decision) *BannedWordFilter {
+	normalized := make(map[string]bool, len(words))
+	for _, word := range words {
+		tokens := tokenize(word, true)
+		if len(tokens) > 0 {
+			normalized[strings.Join(tokens, " ")] = true
+		}
+	}
+	return &BannedWordFilter{words: normalized, decision: decision}
+}
+
+func (f *BannedWordFilter) Name() string {
+	return "banned_words"
+}
+
+func (f *BannedWordFilter) Check(ctx context.Context, s *Submission) (Result, error) {
+	if len(f.words) == 0 {
+		return Result{Decision: Allow}, nil
+	}
+
+	for _, leet := range []bool{true, false} {
+		if match := f.match(tokenize(s.Content, leet)); match != "" {
+			return Result{Decision: f.decision, Reason: "contains banned word: " + match}, nil
+		}
+	}
+
+	return Result{Decision: Allow}, nil
+}
+
+// match returns the first banned phrase of up to maxPhraseWords tokens, as
+// written or with stretched letters collapsed
+func (f *BannedWordFilter) match(tokens []string) string {
+	collapsed := make([]string, len(tokens))
+	for i, token := range tokens {
+		collapsed[i] = collapseRepeats(token)
+	}
+
+	for i := range tokens {
+		for n := 1; n <= maxPhraseWords && i+n <= len(tokens); n++ {
+			for _, candidate := range []string{
+				strings.Join(tokens[i:i+n], " "),
+				strings.Join(collapsed[i:i+n], " "),
+			} {
+				if f.words[candidate] {
+					return candidate
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// tokenize lowercases text and splits it into words, undoing leetspeak when
+// leet is set
+func tokenize(text string, leet bool) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if mapped, ok := leetspeak[r]; ok && leet {
+			r = mapped
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// collapseRepeats shortens runs of the same letter to a single letter
+func collapseRepeats(word string) string {
+	var b strings.Builder
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			continue
+		}
+		b.WriteRune(r)
+		last = r
+	}
+	return b.String()
+}
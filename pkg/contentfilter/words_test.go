@@ -0,0 +1,86 @@
+package contentfilter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		leet bool
+		want []string
+	}{
+		{text: "Hello, World", leet: true, want: []string{"hello", "world"}},
+		{text: "h3ll0 w0rld", leet: true, want: []string{"hello", "world"}},
+		{text: "h3ll0 w0rld", leet: false, want: []string{"h", "ll", "w", "rld"}},
+		{text: "that is bad!", leet: true, want: []string{"that", "is", "badi"}},
+		{text: "that is bad!", leet: false, want: []string{"that", "is", "bad"}},
+		{text: "$5 for b@d stuff", leet: true, want: []string{"ss", "for", "bad", "stuff"}},
+		{text: "  ", leet: true, want: []string{}},
+	}
+	for _, tt := range tests {
+		got := tokenize(tt.text, tt.leet)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q, %v) = %q, want %q", tt.text, tt.leet, got, tt.want)
+		}
+	}
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"baaaad", "bad"},
+		{"bad", "bad"},
+		{"aaa", "a"},
+		{"", ""},
+		{"ééé", "é"},
+	}
+	for _, tt := range tests {
+		if got := collapseRepeats(tt.word); got != tt.want {
+			t.Errorf("collapseRepeats(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestBannedWordFilter(t *testing.T) {
+	filter := NewBannedWordFilter([]string{"bad", "Very Rude Phrase", "  "}, Reject)
+
+	tests := []struct {
+		content string
+		want    Decision
+	}{
+		{"a perfectly fine post", Allow},
+		{"this is bad", Reject},
+		{"that is bad!", Reject},
+		{"(bad), really", Reject},
+		{"BAD", Reject},
+		{"b4d", Reject},
+		{"b@aaad", Reject},
+		{"baaaaad", Reject},
+		{"a very rude phrase here", Reject},
+		{"very-rude...phrase", Reject},
+		{"very rude", Allow},
+		{"badge", Allow},
+		{"ba d", Allow},
+	}
+	for _, tt := range tests {
+		result, err := filter.Check(context.Background(), &Submission{Content: tt.content})
+		if err != nil {
+			t.Fatalf("Check(%q) error: %v", tt.content, err)
+		}
+		if result.Decision != tt.want {
+			t.Errorf("Check(%q) = %s (%s), want %s", tt.content, result.Decision, result.Reason, tt.want)
+		}
+	}
+}
+
+func TestBannedWordFilterEmptyList(t *testing.T) {
+	result, err := NewBannedWordFilter(nil, Reject).Check(context.Background(), &Submission{Content: "anything"})
+	if err != nil || result.Decision != Allow {
+		t.Errorf("Check() = %+v, %v, want allow", result, err)
+	}
+}
@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogMailer writes emails to the application log. It is meant for local development.
+type LogMailer struct {
+	logger *logrus.Logger
+	from   string
+}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer(logger *logrus.Logger, from string) *LogMailer {
+	return &LogMailer{logger: logger, from: from}
+}
+
+// Send logs the message instead of delivering it
+func (m *LogMailer) Send(msg *Message) error {
+	m.logger.WithFields(logrus.Fields{
+		"from":    m.from,
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info(msg.Body)
+	return nil
+}
+
+// FileMailer appends emails to a file. It is meant for local development.
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+// NewFileMailer creates a new file mailer
+func NewFileMailer(path, from string) *FileMailer {
+	return &FileMailer{path: path, from: from}
+}
+
+// Send appends the message to the mail file
+func (m *FileMailer) Send(msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(formatMessage(m.from, msg)); err != nil {
+		return fmt.Errorf("failed to write mail file: %v", err)
+	}
+	_, err = f.WriteString("\r\n\r\n")
+	return err
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewMailer creates the mailer selected by cfg.Driver ("smtp", "file" or "log")
+func NewMailer(cfg *config.MailerConfig, logger *logrus.Logger) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "file":
+		return NewFileMailer(cfg.FilePath, cfg.From), nil
+	case "log", "":
+		return NewLogMailer(logger, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+)
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(cfg *config.MailerConfig) *SMTPMailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPMailer{
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+// Send delivers the message to the SMTP server
+func (m *SMTPMailer) Send(msg *Message) error {
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, formatMessage(m.from, msg)); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return nil
+}
+
+// formatMessage renders the message as an RFC 5322 email
+func formatMessage(from string, msg *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
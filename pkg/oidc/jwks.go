@@ -0,0 +1,134 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// minKeyRefreshInterval limits how often an unknown key id triggers a JWKS refetch
+const minKeyRefreshInterval = 1 * time.Minute
+
+// jwks is a JSON Web Key Set as served by the provider
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet holds parsed public keys by key id
+type keySet struct {
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// parse converts the RSA and EC signing keys of the set, skipping other keys
+func (s *jwks) parse() (*keySet, error) {
+	keys := make(map[string]interface{}, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		switch k.Kty {
+		case "RSA":
+			key, err := k.rsaKey()
+			if err != nil {
+				return nil, err
+			}
+			keys[k.Kid] = key
+		case "EC":
+			key, err := k.ecKey()
+			if err != nil {
+				return nil, err
+			}
+			keys[k.Kid] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable signing keys")
+	}
+	return &keySet{keys: keys, fetchedAt: time.Now()}, nil
+}
+
+// find returns the key with the given id if it matches the signing method
+func (s *keySet) find(kid string, method jwt.SigningMethod) (interface{}, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", method.Alg())
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", method.Alg())
+		}
+	}
+	return key, nil
+}
+
+func (k *jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rsa modulus: %v", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rsa exponent: %v", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (k *jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ec x coordinate: %v", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ec y coordinate: %v", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
@@ -0,0 +1,263 @@
+// Package oidctest provides a local OpenID Connect provider for tests. It
+// serves discovery, JWKS, token and userinfo endpoints and checks PKCE.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// grant is an issued authorization code waiting to be exchanged
+type grant struct {
+	challenge   string
+	redirectURI string
+	claims      jwt.MapClaims
+}
+
+type signingKey struct {
+	id  string
+	key *rsa.PrivateKey
+}
+
+// Server is a mock provider. Users "consent" through Authorize instead of a
+// login page.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu           sync.Mutex
+	keys         []*signingKey
+	grants       map[string]*grant
+	accessTokens map[string]jwt.MapClaims
+	jwksRequests int
+	omitIDToken  bool
+}
+
+// NewServer starts a provider with a single RSA signing key
+func NewServer(clientID, clientSecret string) *Server {
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		grants:       make(map[string]*grant),
+		accessTokens: make(map[string]jwt.MapClaims),
+	}
+	s.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/userinfo", s.handleUserInfo)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Issuer returns the issuer of the provider
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// KeyID returns the id of the key new ID tokens are signed with
+func (s *Server) KeyID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[len(s.keys)-1].id
+}
+
+// RotateKey adds a new signing key. Older keys stay published.
+func (s *Server) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, &signingKey{id: fmt.Sprintf("key-%d", len(s.keys)+1), key: key})
+}
+
+// JWKSRequests returns how often the key set was fetched
+func (s *Server) JWKSRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jwksRequests
+}
+
+// OmitIDToken makes the token endpoint answer like a plain OAuth2 provider
+func (s *Server) OmitIDToken(omit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.omitIDToken = omit
+}
+
+// Authorize plays the user approving the login at authURL. It returns the
+// state and code the provider would redirect back with. The ID token gets the
+// given claims on top of iss, aud, exp, iat and the nonce of the request, so
+// tests can override any of them.
+func (s *Server) Authorize(authURL string, claims jwt.MapClaims) (state, code string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+	if query.Get("client_id") != s.ClientID {
+		return "", "", fmt.Errorf("unknown client %q", query.Get("client_id"))
+	}
+	if query.Get("code_challenge_method") != "S256" {
+		return "", "", fmt.Errorf("unsupported code challenge method %q", query.Get("code_challenge_method"))
+	}
+
+	now := time.Now()
+	idClaims := jwt.MapClaims{
+		"iss":   s.Issuer(),
+		"aud":   s.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for name, value := range claims {
+		idClaims[name] = value
+	}
+
+	code = randomString()
+	s.mu.Lock()
+	s.grants[code] = &grant{
+		challenge:   query.Get("code_challenge"),
+		redirectURI: query.Get("redirect_uri"),
+		claims:      idClaims,
+	}
+	s.mu.Unlock()
+
+	return query.Get("state"), code, nil
+}
+
+// SignIDToken signs claims with the current key
+func (s *Server) SignIDToken(claims jwt.MapClaims) string {
+	s.mu.Lock()
+	key := s.keys[len(s.keys)-1]
+	s.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.id
+	signed, err := token.SignedString(key.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+		"userinfo_endpoint":      s.URL + "/userinfo",
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.jwksRequests++
+	keys := make([]map[string]string, len(s.keys))
+	for i, k := range s.keys {
+		keys[i] = map[string]string{
+			"kty": "RSA",
+			"kid": k.id,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	if r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Codes are single use, a failed exchange burns them too
+	s.mu.Lock()
+	g, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	omitIDToken := s.omitIDToken
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	accessToken := randomString()
+	s.mu.Lock()
+	s.accessTokens[accessToken] = g.claims
+	s.mu.Unlock()
+
+	response := map[string]string{"access_token": accessToken, "token_type": "Bearer"}
+	if !omitIDToken {
+		response["id_token"] = s.SignIDToken(g.claims)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || auth[:len(prefix)] != prefix {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+
+	s.mu.Lock()
+	claims, ok := s.accessTokens[auth[len(prefix):]]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, claims)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
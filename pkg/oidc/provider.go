@@ -0,0 +1,375 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// discoveryPath is appended to the issuer to find the provider metadata
+const discoveryPath = "/.well-known/openid-configuration"
+
+// Claims holds the identity information returned by a provider
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	GivenName         string
+	FamilyName        string
+	PreferredUsername string
+}
+
+// TokenResponse is the result of exchanging an authorization code
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// metadata holds the provider endpoints, from discovery or configuration
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// Provider performs the authorization code flow with PKCE against an OpenID
+// Connect provider. Providers without ID tokens (plain OAuth2, e.g. GitHub)
+// are supported through their userinfo endpoint.
+type Provider struct {
+	name   string
+	config *config.OAuthProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     *keySet
+}
+
+// NewProvider creates a new provider
+func NewProvider(name string, cfg *config.OAuthProviderConfig) *Provider {
+	return &Provider{
+		name:   name,
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the URL to send the user to for authorization
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return md.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades an authorization code and its PKCE verifier for tokens
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokens TokenResponse
+	if err := p.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %v", err)
+	}
+	if tokens.AccessToken == "" && tokens.IDToken == "" {
+		return nil, errors.New("failed to exchange code: no token returned")
+	}
+
+	return &tokens, nil
+}
+
+// Identify returns the claims for a token response, verifying the ID token
+// when there is one and falling back to the userinfo endpoint otherwise
+func (p *Provider) Identify(ctx context.Context, tokens *TokenResponse, nonce string) (*Claims, error) {
+	if tokens.IDToken != "" {
+		return p.VerifyIDToken(ctx, tokens.IDToken, nonce)
+	}
+	return p.UserInfo(ctx, tokens.AccessToken)
+}
+
+// VerifyIDToken checks the signature of an ID token against the provider JWKS
+// and validates its issuer, audience, expiry and nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	parsedToken, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, md.JWKSURI, kid, token.Method)
+	})
+	if err != nil || !parsedToken.Valid {
+		return nil, fmt.Errorf("invalid id token: %v", err)
+	}
+
+	if !claims.VerifyIssuer(md.Issuer, true) {
+		return nil, errors.New("invalid id token: issuer mismatch")
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("invalid id token: audience mismatch")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("invalid id token: expired")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	return claimsFromMap(claims), nil
+}
+
+// UserInfo fetches the claims of the user behind an access token
+func (p *Provider) UserInfo(ctx context.Context, accessToken string) (*Claims, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if md.UserInfoEndpoint == "" {
+		return nil, errors.New("provider has no userinfo endpoint")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, md.UserInfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	info := map[string]interface{}{}
+	if err := p.doJSON(req, &info); err != nil {
+		return nil, fmt.Errorf("failed to fetch userinfo: %v", err)
+	}
+
+	claims := claimsFromMap(info)
+	if claims.Subject == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+	return claims, nil
+}
+
+// getMetadata returns the provider endpoints, discovering them from the issuer
+// the first time unless they are all configured explicitly
+func (p *Provider) getMetadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	md := &metadata{
+		Issuer:                p.config.Issuer,
+		AuthorizationEndpoint: p.config.AuthURL,
+		TokenEndpoint:         p.config.TokenURL,
+		JWKSURI:               p.config.JWKSURL,
+		UserInfoEndpoint:      p.config.UserInfoURL,
+	}
+
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || (md.JWKSURI == "" && md.UserInfoEndpoint == "") {
+		if p.config.Issuer == "" {
+			return nil, fmt.Errorf("provider %s needs an issuer or explicit endpoints", p.name)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+discoveryPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		var discovered metadata
+		if err := p.doJSON(req, &discovered); err != nil {
+			return nil, fmt.Errorf("failed to discover provider %s: %v", p.name, err)
+		}
+		if discovered.Issuer != p.config.Issuer {
+			return nil, fmt.Errorf("provider %s issuer mismatch: %s", p.name, discovered.Issuer)
+		}
+
+		// Explicit configuration wins over discovery
+		if md.AuthorizationEndpoint == "" {
+			md.AuthorizationEndpoint = discovered.AuthorizationEndpoint
+		}
+		if md.TokenEndpoint == "" {
+			md.TokenEndpoint = discovered.TokenEndpoint
+		}
+		if md.JWKSURI == "" {
+			md.JWKSURI = discovered.JWKSURI
+		}
+		if md.UserInfoEndpoint == "" {
+			md.UserInfoEndpoint = discovered.UserInfoEndpoint
+		}
+	}
+
+	p.metadata = md
+	return md, nil
+}
+
+// getKey returns the JWKS key with the given id, refetching the key set when
+// the id is unknown so that provider key rotation is picked up
+func (p *Provider) getKey(ctx context.Context, jwksURI, kid string, method jwt.SigningMethod) (interface{}, error) {
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	if keys != nil {
+		if key, err := keys.find(kid, method); err == nil {
+			return key, nil
+		}
+		if time.Since(keys.fetchedAt) < minKeyRefreshInterval {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var raw jwks
+	if err := p.doJSON(req, &raw); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %v", err)
+	}
+	keys, err = raw.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return keys.find(kid, method)
+}
+
+// doJSON performs a request and decodes a successful JSON response into v
+func (p *Provider) doJSON(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// claimsFromMap reads standard OIDC claims, accepting the common field names
+// of OAuth2-only userinfo responses ("id", "login") as fallbacks
+func claimsFromMap(m map[string]interface{}) *Claims {
+	claims := &Claims{
+		Subject:           stringClaim(m, "sub"),
+		Email:             stringClaim(m, "email"),
+		GivenName:         stringClaim(m, "given_name"),
+		FamilyName:        stringClaim(m, "family_name"),
+		PreferredUsername: stringClaim(m, "preferred_username"),
+	}
+	if claims.Subject == "" {
+		claims.Subject = stringClaim(m, "id")
+	}
+	if claims.PreferredUsername == "" {
+		claims.PreferredUsername = stringClaim(m, "login")
+	}
+
+	// Some providers send email_verified as a string
+	switch v := m["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = v
+	case string:
+		claims.EmailVerified = v == "true"
+	}
+
+	return claims
+}
+
+func stringClaim(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+// GenerateState returns a random value for the state and nonce parameters
+func GenerateState() (string, error) {
+	return randomString(32)
+}
+
+// GenerateCodeVerifier returns a PKCE code verifier and its S256 challenge
+func GenerateCodeVerifier() (string, string, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,275 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dang-Hai-Tran/newfeed-go/config"
+	"github.com/Dang-Hai-Tran/newfeed-go/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const testRedirectURL = "https://app.example.com/v1/oauth/test/callback"
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+	server := oidctest.NewServer("client-id", "client-secret")
+	t.Cleanup(server.Close)
+
+	provider := NewProvider("test", &config.OAuthProviderConfig{
+		Issuer:       server.Issuer(),
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	return provider, server
+}
+
+// startLogin runs the redirect half of a login and returns the code together
+// with the nonce and verifier the caller has to remember
+func startLogin(t *testing.T, provider *Provider, server *oidctest.Server, claims jwt.MapClaims) (code, nonce, verifier string) {
+	t.Helper()
+	state, _ := GenerateState()
+	nonce, _ = GenerateState()
+	verifier, challenge, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, challenge)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error: %v", err)
+	}
+	if !strings.HasPrefix(authURL, server.URL+"/authorize?") {
+		t.Fatalf("AuthCodeURL() = %s, want the discovered authorization endpoint", authURL)
+	}
+
+	returnedState, code, err := server.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatalf("Authorize() error: %v", err)
+	}
+	if returnedState != state {
+		t.Fatalf("state = %s, want %s", returnedState, state)
+	}
+	return code, nonce, verifier
+}
+
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":                "user-1",
+		"email":              "jane@example.com",
+		"email_verified":     true,
+		"given_name":         "Jane",
+		"family_name":        "Doe",
+		"preferred_username": "jane",
+	}
+}
+
+func TestLoginWithIDToken(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+	code, nonce, verifier := startLogin(t, provider, server, userClaims())
+
+	tokens, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange() error: %v", err)
+	}
+	if tokens.IDToken == "" {
+		t.Fatal("Exchange() returned no id token")
+	}
+
+	claims, err := provider.Identify(ctx, tokens, nonce)
+	if err != nil {
+		t.Fatalf("Identify() error: %v", err)
+	}
+	want := Claims{
+		Subject:           "user-1",
+		Email:             "jane@example.com",
+		EmailVerified:     true,
+		GivenName:         "Jane",
+		FamilyName:        "Doe",
+		PreferredUsername: "jane",
+	}
+	if *claims != want {
+		t.Errorf("Identify() = %+v, want %+v", *claims, want)
+	}
+}
+
+func TestLoginWithUserInfo(t *testing.T) {
+	provider, server := newTestProvider(t)
+	server.OmitIDToken(true)
+	ctx := context.Background()
+	code, nonce, verifier := startLogin(t, provider, server, userClaims())
+
+	tokens, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange() error: %v", err)
+	}
+	claims, err := provider.Identify(ctx, tokens, nonce)
+	if err != nil {
+		t.Fatalf("Identify() error: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "jane@example.com" {
+		t.Errorf("Identify() = %+v, want the userinfo claims", *claims)
+	}
+	if got := server.JWKSRequests(); got != 0 {
+		t.Errorf("jwks fetched %d times without an id token", got)
+	}
+}
+
+func TestExchangeChecksCode(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code, _, _ := startLogin(t, provider, server, userClaims())
+		otherVerifier, _, _ := GenerateCodeVerifier()
+		if _, err := provider.Exchange(ctx, code, otherVerifier); err == nil {
+			t.Error("Exchange() accepted a code with the wrong PKCE verifier")
+		}
+	})
+
+	t.Run("reused code", func(t *testing.T) {
+		code, _, verifier := startLogin(t, provider, server, userClaims())
+		if _, err := provider.Exchange(ctx, code, verifier); err != nil {
+			t.Fatalf("first Exchange() error: %v", err)
+		}
+		if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+			t.Error("Exchange() accepted a code twice")
+		}
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		_, _, verifier := startLogin(t, provider, server, userClaims())
+		if _, err := provider.Exchange(ctx, "made-up", verifier); err == nil {
+			t.Error("Exchange() accepted an unknown code")
+		}
+	})
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+
+	validClaims := func() jwt.MapClaims {
+		claims := userClaims()
+		claims["iss"] = server.Issuer()
+		claims["aud"] = "client-id"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		claims["nonce"] = "nonce-1"
+		return claims
+	}
+	// Make sure the key set is loaded so the checks below are not about fetching it
+	if _, err := provider.VerifyIDToken(ctx, server.SignIDToken(validClaims()), "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken() rejected a valid token: %v", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+	forged.Header["kid"] = server.KeyID()
+	forgedToken, err := forged.SignedString(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+	unsigned.Header["kid"] = server.KeyID()
+	unsignedToken, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		nonce  string
+		modify func(jwt.MapClaims)
+	}{
+		{name: "nonce mismatch", nonce: "nonce-2"},
+		{name: "missing nonce", nonce: "nonce-1", modify: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "wrong audience", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "wrong issuer", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "forged signature", token: forgedToken, nonce: "nonce-1"},
+		{name: "unsigned", token: unsignedToken, nonce: "nonce-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.token
+			if token == "" {
+				claims := validClaims()
+				if tt.modify != nil {
+					tt.modify(claims)
+				}
+				token = server.SignIDToken(claims)
+			}
+			if _, err := provider.VerifyIDToken(ctx, token, tt.nonce); err == nil {
+				t.Error("VerifyIDToken() accepted an invalid token")
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenKeyRotation(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+
+	claims := userClaims()
+	claims["iss"] = server.Issuer()
+	claims["aud"] = "client-id"
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	claims["nonce"] = "nonce-1"
+
+	if _, err := provider.VerifyIDToken(ctx, server.SignIDToken(claims), "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken() error: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, server.SignIDToken(claims), "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken() error: %v", err)
+	}
+	if got := server.JWKSRequests(); got != 1 {
+		t.Fatalf("jwks fetched %d times, want the key set to be cached", got)
+	}
+
+	// A key id that appears right after a fetch does not trigger another one
+	server.RotateKey()
+	rotated := server.SignIDToken(claims)
+	if _, err := provider.VerifyIDToken(ctx, rotated, "nonce-1"); err == nil {
+		t.Fatal("VerifyIDToken() accepted a key the provider has not fetched")
+	}
+	if got := server.JWKSRequests(); got != 1 {
+		t.Fatalf("jwks fetched %d times within the refresh interval, want 1", got)
+	}
+
+	provider.mu.Lock()
+	provider.keys.fetchedAt = time.Now().Add(-2 * minKeyRefreshInterval)
+	provider.mu.Unlock()
+
+	if _, err := provider.VerifyIDToken(ctx, rotated, "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken() did not pick up the rotated key: %v", err)
+	}
+	if got := server.JWKSRequests(); got != 2 {
+		t.Errorf("jwks fetched %d times, want 2", got)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer("client-id", "client-secret")
+	defer server.Close()
+
+	provider := NewProvider("test", &config.OAuthProviderConfig{
+		Issuer:      server.Issuer() + "/",
+		ClientID:    "client-id",
+		RedirectURL: testRedirectURL,
+	})
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil {
+		t.Error("AuthCodeURL() accepted metadata for a different issuer")
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps uploaded media files and serves them under their URLs
+type Store interface {
+	// Put stores the content of r under name, replacing any file of the
+	// same name, and returns the URL of the file
+	Put(name string, r io.Reader) (string, error)
+	// Open opens the file behind url. URLs the store does not serve fail
+	// with an error satisfying os.IsNotExist.
+	Open(url string) (io.ReadCloser, error)
+	// Delete removes the file behind url. URLs the store does not serve,
+	// like images linked from elsewhere, are ignored.
+	Delete(url string) error
+}
+
+// LocalStore keeps media files in a directory served under baseURL
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates a new local store
+func NewLocalStore(dir, baseURL string) *LocalStore {
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Put writes to a temporary file first, so that a failed write never leaves
+// a partial file behind
+func (s *LocalStore) Put(name string, r io.Reader) (string, error) {
+	name = filepath.Clean("/" + name)
+	if name == "/" {
+		return "", os.ErrInvalid
+	}
+	path := filepath.Join(s.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + filepath.ToSlash(name), nil
+}
+
+func (s *LocalStore) Open(url string) (io.ReadCloser, error) {
+	name, ok := s.name(url)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+func (s *LocalStore) Delete(url string) error {
+	name, ok := s.name(url)
+	if !ok {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(s.dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// name returns the file name of url inside the store directory
+func (s *LocalStore) name(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.baseURL+"/") {
+		return "", false
+	}
+
+	// Clean against the root so that ".." cannot leave the directory
+	name := filepath.Clean("/" + strings.TrimPrefix(url, s.baseURL+"/"))
+	if name == "/" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,80 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Token types, stored in the "typ" claim
+const (
+	TypeAccess    = "access"
+	TypeChallenge = "2fa_challenge"
+)
+
+// Manager issues and parses HMAC signed JWTs
+type Manager struct {
+	secret          []byte
+	accessExpiry    time.Duration
+	challengeExpiry time.Duration
+}
+
+// NewManager creates a new token manager
+func NewManager(secret string, accessExpiry, challengeExpiry time.Duration) *Manager {
+	return &Manager{
+		secret:          []byte(secret),
+		accessExpiry:    accessExpiry,
+		challengeExpiry: challengeExpiry,
+	}
+}
+
+// GenerateAccessToken issues an access token for a user carrying their role
+func (m *Manager) GenerateAccessToken(userID uint64, role string) (string, error) {
+	return m.generate(userID, TypeAccess, role, m.accessExpiry)
+}
+
+// GenerateChallengeToken issues a short-lived token proving the user passed the
+// password step of a two-factor login. It cannot be used as an access token.
+func (m *Manager) GenerateChallengeToken(userID uint64) (string, error) {
+	return m.generate(userID, TypeChallenge, "", m.challengeExpiry)
+}
+
+// Parse validates a token of the expected type and returns its user ID
+func (m *Manager) Parse(tokenString, expectedType string) (uint64, error) {
+	claims := jwt.MapClaims{}
+	parsedToken, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return m.secret, nil
+	})
+	if err != nil || !parsedToken.Valid {
+		return 0, errors.New("invalid token")
+	}
+
+	if typ, _ := claims["typ"].(string); typ != expectedType {
+		return 0, errors.New("invalid token type")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("invalid token claims")
+	}
+	return uint64(userID), nil
+}
+
+func (m *Manager) generate(userID uint64, typ, role string, expiry time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"typ":     typ,
+		"iat":     now.Unix(),
+		"exp":     now.Add(expiry).Unix(),
+	}
+	if role != "" {
+		claims["role"] = role
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
@@ -0,0 +1,109 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 4226 and RFC 6238 test vectors
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+// TestGenerateCodeRFC6238 checks the SHA-1 vectors of RFC 6238 Appendix B.
+// The RFC lists 8 digit codes, 6 digit codes are their last 6 digits.
+func TestGenerateCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode(%d) error: %v", tt.unix, err)
+		}
+		if want := tt.want[len(tt.want)-Digits:]; got != want {
+			t.Errorf("GenerateCode(%d) = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+// TestGenerateCodeRFC4226 checks the HOTP values of RFC 4226 Appendix D
+func TestGenerateCodeRFC4226(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		got, err := generateCode(rfcSecret, int64(counter))
+		if err != nil {
+			t.Fatalf("generateCode(%d) error: %v", counter, err)
+		}
+		if got != code {
+			t.Errorf("generateCode(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestGenerateCodeAcceptsLowercaseAndPadding(t *testing.T) {
+	at := time.Unix(59, 0)
+	want, _ := GenerateCode(rfcSecret, at)
+
+	for _, secret := range []string{strings.ToLower(rfcSecret), rfcSecret + "===="} {
+		got, err := GenerateCode(secret, at)
+		if err != nil || got != want {
+			t.Errorf("GenerateCode(%q) = %s, %v, want %s", secret, got, err, want)
+		}
+	}
+}
+
+func TestGenerateCodeInvalidSecret(t *testing.T) {
+	if _, err := GenerateCode("not base32!", time.Now()); err == nil {
+		t.Error("GenerateCode accepted an invalid secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	counter := Counter(now)
+	previous, _ := GenerateCode(rfcSecret, now.Add(-Period*time.Second))
+	current, _ := GenerateCode(rfcSecret, now)
+	tooOld, _ := GenerateCode(rfcSecret, now.Add(-2*Period*time.Second))
+
+	tests := []struct {
+		name        string
+		code        string
+		skew        int
+		wantCounter int64
+		wantOK      bool
+	}{
+		{"current step", current, 0, counter, true},
+		{"previous step within skew", previous, 1, counter - 1, true},
+		{"previous step without skew", previous, 0, 0, false},
+		{"outside skew", tooOld, 1, 0, false},
+		{"wrong length", current[:Digits-1], 1, 0, false},
+		{"wrong code", "000000", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCounter, ok := Validate(rfcSecret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || gotCounter != tt.wantCounter {
+				t.Errorf("Validate() = %d, %v, want %d, %v", gotCounter, ok, tt.wantCounter, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != secretSize {
+		t.Errorf("secret %q decodes to %d bytes, %v", secret, len(key), err)
+	}
+}
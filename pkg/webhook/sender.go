@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody caps how much of the receiver's response is kept
+const maxResponseBody = 4096
+
+// Config holds delivery configuration. AllowPrivateTargets lets receivers
+// use http and private addresses, for local development only.
+type Config struct {
+	Timeout             time.Duration
+	MaxAttempts         int
+	InitialBackoff      time.Duration
+	MaxBackoff          time.Duration
+	AllowPrivateTargets bool
+}
+
+// DefaultConfig returns default delivery configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:        10 * time.Second,
+		MaxAttempts:    5,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     1 * time.Minute,
+	}
+}
+
+// Request describes a single webhook delivery
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID uint64
+	Body       []byte
+}
+
+// Result holds the outcome of one delivery attempt
+type Result struct {
+	Attempt      int
+	StatusCode   int
+	ResponseBody string
+	Err          error
+}
+
+// Success reports whether the receiver acknowledged the delivery
+func (r *Result) Success() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Sender signs and posts webhook payloads with retry and exponential backoff
+type Sender struct {
+	client *http.Client
+	config *Config
+}
+
+// NewSender creates a new webhook sender
+func NewSender(config *Config) *Sender {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateTargets {
+		dialer.Control = checkDialAddress
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// No proxy, so that the dialer sees the receiver's address
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: config.Timeout,
+			},
+			// Redirects are not followed, a redirect is a failed delivery
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: config,
+	}
+}
+
+// Sign computes the hex encoded HMAC-SHA256 of "timestamp.body" using secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value produced by Sign
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := "sha256=" + Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Deliver sends the request until it succeeds or the attempts are exhausted,
+// calling onAttempt after every attempt. It returns the last result.
+func (s *Sender) Deliver(ctx context.Context, req *Request, onAttempt func(*Result)) *Result {
+	var result *Result
+
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		result = s.send(ctx, req)
+		result.Attempt = attempt
+		if onAttempt != nil {
+			onAttempt(result)
+		}
+		if result.Success() || attempt == s.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(s.backoff(attempt)):
+		}
+	}
+
+	return result
+}
+
+// backoff returns the wait after a failed attempt, doubling from
+// InitialBackoff up to MaxBackoff
+func (s *Sender) backoff(attempt int) time.Duration {
+	delay := s.config.InitialBackoff
+	for i := 1; i < attempt && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		return s.config.MaxBackoff
+	}
+	return delay
+}
+
+// send performs a single signed POST to the receiver
+func (s *Sender) send(ctx context.Context, req *Request) *Result {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return &Result{Err: err}
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatUint(req.DeliveryID, 10))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, "sha256="+Sign(req.Secret, timestamp, req.Body))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return &Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := &Result{
+		StatusCode:   resp.StatusCode,
+		ResponseBody: string(body),
+	}
+	if !result.Success() {
+		result.Err = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return result
+}
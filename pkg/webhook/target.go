@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbiddenTarget is returned for receivers on loopback, private, link-local
+// or otherwise reserved addresses
+var ErrForbiddenTarget = errors.New("webhook url must point to a public address")
+
+// reservedNetworks are the special-purpose ranges not covered by the net.IP
+// predicates, such as carrier-grade NAT and documentation ranges
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"100::/64",
+	"2001:db8::/32",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateURL checks that rawURL is an https URL whose host resolves only to
+// public addresses. Addresses are checked again when connecting, since the
+// host may resolve differently by then.
+func (s *Sender) ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return errors.New("invalid webhook url")
+	}
+	if s.config.AllowPrivateTargets {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.New("invalid webhook url")
+		}
+		return nil
+	}
+	if u.Scheme != "https" {
+		return errors.New("webhook url must use https")
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("could not resolve webhook host: %v", err)
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
+
+// checkDialAddress runs before every connection the sender opens, after the
+// host name was resolved, so a receiver cannot switch to a private address
+// once its URL was accepted
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}